@@ -0,0 +1,248 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const keyBits = 2048
+
+var ErrUnknownKey = errors.New("chave de assinatura desconhecida")
+
+type SigningKey struct {
+	ID         string
+	PrivateKey *rsa.PrivateKey
+	CreatedAt  time.Time
+	// momento em que a chave deixou de assinar tokens; nil enquanto ativa
+	RetiredAt *time.Time
+}
+
+// KeySet mantém as chaves de assinatura. Apenas uma assina novos tokens,
+// mas as aposentadas continuam validando até que os tokens emitidos expirem.
+type KeySet struct {
+	mu       sync.RWMutex
+	keys     map[string]*SigningKey
+	activeID string
+	tokenTTL time.Duration
+	dir      string
+}
+
+func NewKeySet(dir string, tokenTTL time.Duration) (*KeySet, error) {
+	ks := &KeySet{
+		keys:     map[string]*SigningKey{},
+		tokenTTL: tokenTTL,
+		dir:      dir,
+	}
+
+	if dir != "" {
+		if err := ks.loadDir(); err != nil {
+			return nil, err
+		}
+	}
+
+	if ks.activeID == "" {
+		if _, err := ks.Rotate(); err != nil {
+			return nil, err
+		}
+	}
+
+	return ks, nil
+}
+
+// carrega todos os <kid>.pem do diretório; a chave mais recente passa a assinar
+func (ks *KeySet) loadDir() error {
+	files, err := filepath.Glob(filepath.Join(ks.dir, "*.pem"))
+	if err != nil {
+		return err
+	}
+
+	var loaded []*SigningKey
+	for _, file := range files {
+		key, err := readKey(file)
+		if err != nil {
+			return fmt.Errorf("lendo %s: %w", file, err)
+		}
+		loaded = append(loaded, key)
+	}
+
+	sort.Slice(loaded, func(i, j int) bool {
+		return loaded[i].CreatedAt.Before(loaded[j].CreatedAt)
+	})
+
+	for i, key := range loaded {
+		if i < len(loaded)-1 {
+			retiredAt := loaded[i+1].CreatedAt
+			key.RetiredAt = &retiredAt
+		}
+		ks.keys[key.ID] = key
+		ks.activeID = key.ID
+	}
+
+	return nil
+}
+
+func readKey(file string) (*SigningKey, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("arquivo PEM inválido")
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("apenas chaves RSA são suportadas")
+	}
+
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SigningKey{
+		ID:         strings.TrimSuffix(filepath.Base(file), ".pem"),
+		PrivateKey: privateKey,
+		CreatedAt:  info.ModTime(),
+	}, nil
+}
+
+func writeKey(dir string, key *SigningKey) error {
+	der, err := x509.MarshalPKCS8PrivateKey(key.PrivateKey)
+	if err != nil {
+		return err
+	}
+
+	data := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+	return os.WriteFile(filepath.Join(dir, key.ID+".pem"), data, 0600)
+}
+
+// Rotate gera uma nova chave ativa e aposenta a anterior, que segue
+// publicada no JWKS até o fim do TTL dos tokens.
+func (ks *KeySet) Rotate() (*SigningKey, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, keyBits)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	key := &SigningKey{
+		ID:         fmt.Sprintf("%d", now.UnixNano()),
+		PrivateKey: privateKey,
+		CreatedAt:  now,
+	}
+
+	if ks.dir != "" {
+		if err := writeKey(ks.dir, key); err != nil {
+			return nil, err
+		}
+	}
+
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	if previous, ok := ks.keys[ks.activeID]; ok {
+		previous.RetiredAt = &now
+	}
+	ks.keys[key.ID] = key
+	ks.activeID = key.ID
+	ks.prune(now)
+
+	return key, nil
+}
+
+// remove chaves aposentadas há mais tempo que o TTL dos tokens
+func (ks *KeySet) prune(now time.Time) {
+	for id, key := range ks.keys {
+		if key.RetiredAt != nil && now.Sub(*key.RetiredAt) > ks.tokenTTL {
+			delete(ks.keys, id)
+			if ks.dir != "" {
+				os.Remove(filepath.Join(ks.dir, id+".pem"))
+			}
+		}
+	}
+}
+
+func (ks *KeySet) active() *SigningKey {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	return ks.keys[ks.activeID]
+}
+
+func (ks *KeySet) lookup(kid string) (*SigningKey, error) {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	if key.RetiredAt != nil && time.Since(*key.RetiredAt) > ks.tokenTTL {
+		return nil, ErrUnknownKey
+	}
+
+	return key, nil
+}
+
+type JWK struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS retorna as chaves públicas ainda válidas para verificação
+func (ks *KeySet) JWKS() JWKS {
+	ks.mu.RLock()
+	defer ks.mu.RUnlock()
+
+	jwks := JWKS{Keys: []JWK{}}
+	for _, key := range ks.keys {
+		if key.RetiredAt != nil && time.Since(*key.RetiredAt) > ks.tokenTTL {
+			continue
+		}
+
+		publicKey := key.PrivateKey.PublicKey
+		jwks.Keys = append(jwks.Keys, JWK{
+			Kty: "RSA",
+			Use: "sig",
+			Alg: "RS256",
+			Kid: key.ID,
+			N:   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+		})
+	}
+
+	sort.Slice(jwks.Keys, func(i, j int) bool {
+		return jwks.Keys[i].Kid < jwks.Keys[j].Kid
+	})
+
+	return jwks
+}
@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"os"
+)
+
+// ClientTLSConfig exige e valida certificados de cliente emitidos pela CA informada
+func ClientTLSConfig(clientCAFile string) (*tls.Config, error) {
+	data, err := os.ReadFile(clientCAFile)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, errors.New("nenhum certificado válido na CA de clientes")
+	}
+
+	return &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  pool,
+		MinVersion: tls.VersionTLS12,
+	}, nil
+}
+
+// CertificateIdentities lista as identidades do certificado, URI SANs (ex.: SPIFFE) antes do CN
+func CertificateIdentities(cert *x509.Certificate) []string {
+	var identities []string
+	for _, uri := range cert.URIs {
+		identities = append(identities, uri.String())
+	}
+	if cert.Subject.CommonName != "" {
+		identities = append(identities, cert.Subject.CommonName)
+	}
+	return identities
+}
@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"sync"
+	"time"
+)
+
+// RevocationStore guarda as sessões revogadas enquanto ainda podem existir
+// access tokens emitidos para elas
+type RevocationStore interface {
+	Revoke(sessionID string, until time.Time)
+	IsRevoked(sessionID string) bool
+}
+
+type MemoryRevocationStore struct {
+	mu      sync.RWMutex
+	revoked map[string]time.Time
+}
+
+func NewMemoryRevocationStore() *MemoryRevocationStore {
+	return &MemoryRevocationStore{
+		revoked: map[string]time.Time{},
+	}
+}
+
+func (s *MemoryRevocationStore) Revoke(sessionID string, until time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for id, expiry := range s.revoked {
+		if now.After(expiry) {
+			delete(s.revoked, id)
+		}
+	}
+	s.revoked[sessionID] = until
+}
+
+func (s *MemoryRevocationStore) IsRevoked(sessionID string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	until, ok := s.revoked[sessionID]
+	return ok && time.Now().Before(until)
+}
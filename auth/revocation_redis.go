@@ -0,0 +1,58 @@
+package auth
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const revokedKeyPrefix = "revoked:session:"
+
+// RedisRevocationStore compartilha as revogações entre as instâncias. Também as guarda em
+// memória: a revogação feita nesta instância vale mesmo com o Redis fora do ar
+type RedisRevocationStore struct {
+	client  *redis.Client
+	local   *MemoryRevocationStore
+	timeout time.Duration
+}
+
+func NewRedisRevocationStore(client *redis.Client) *RedisRevocationStore {
+	return &RedisRevocationStore{
+		client:  client,
+		local:   NewMemoryRevocationStore(),
+		timeout: 500 * time.Millisecond,
+	}
+}
+
+func (s *RedisRevocationStore) Revoke(sessionID string, until time.Time) {
+	s.local.Revoke(sessionID, until)
+
+	ttl := time.Until(until)
+	if ttl <= 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	if err := s.client.Set(ctx, revokedKeyPrefix+sessionID, 1, ttl).Err(); err != nil {
+		log.Printf("revogação da sessão %s não propagada ao Redis: %v", sessionID, err)
+	}
+}
+
+// IsRevoked consulta o Redis apenas quando a revogação não é conhecida localmente
+func (s *RedisRevocationStore) IsRevoked(sessionID string) bool {
+	if s.local.IsRevoked(sessionID) {
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+	defer cancel()
+	exists, err := s.client.Exists(ctx, revokedKeyPrefix+sessionID).Result()
+	if err != nil {
+		log.Printf("consulta de revogação no Redis: %v", err)
+		return false
+	}
+	return exists > 0
+}
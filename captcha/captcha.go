@@ -0,0 +1,78 @@
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pytsx/goapi/httpclient"
+)
+
+// endpoints de verificação; os três provedores seguem o mesmo protocolo siteverify
+var endpoints = map[string]string{
+	"recaptcha": "https://www.google.com/recaptcha/api/siteverify",
+	"hcaptcha":  "https://api.hcaptcha.com/siteverify",
+	"turnstile": "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+}
+
+type Verifier interface {
+	Verify(ctx context.Context, token, remoteIP string) (bool, error)
+}
+
+type siteVerifier struct {
+	endpoint string
+	secret   string
+	client   *httpclient.Client
+}
+
+func NewVerifier(provider, secret string, outbound httpclient.Config) (Verifier, error) {
+	endpoint, ok := endpoints[provider]
+	if !ok {
+		return nil, fmt.Errorf("provedor de CAPTCHA desconhecido: %s", provider)
+	}
+
+	return &siteVerifier{
+		endpoint: endpoint,
+		secret:   secret,
+		// sem novas tentativas: o token é de uso único e o provedor pode tê-lo consumido
+		// mesmo quando a resposta não chega
+		client: httpclient.New("captcha", outbound.WithTimeout(5*time.Second).WithRetries(0)),
+	}, nil
+}
+
+func (v *siteVerifier) Verify(ctx context.Context, token, remoteIP string) (bool, error) {
+	form := url.Values{
+		"secret":   {v.secret},
+		"response": {token},
+		"remoteip": {remoteIP},
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("verificação de CAPTCHA retornou %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Success bool `json:"success"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, err
+	}
+
+	return result.Success, nil
+}
@@ -0,0 +1,65 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync/atomic"
+	"time"
+)
+
+var ErrInjected = errors.New("chaos: falha injetada")
+
+// Config define a fração (0 a 1) de requisições/consultas afetadas por cada tipo de falha
+type Config struct {
+	Latency       time.Duration
+	LatencyRate   float64
+	HTTPErrorRate float64
+	DBErrorRate   float64
+	DBDropRate    float64
+}
+
+// Injector sorteia as falhas; usado pelo middleware HTTP e pelo decorator do banco.
+// Só injeta depois de Arm, para não atrapalhar migrações e verificações do boot.
+type Injector struct {
+	cfg   Config
+	armed atomic.Bool
+}
+
+func NewInjector(cfg Config) *Injector {
+	return &Injector{cfg: cfg}
+}
+
+func (i *Injector) Arm() {
+	i.armed.Store(true)
+}
+
+func (i *Injector) roll(rate float64) bool {
+	return i.armed.Load() && rate > 0 && rand.Float64() < rate
+}
+
+// Delay aplica a latência sorteada, respeitando o cancelamento do contexto
+func (i *Injector) Delay(ctx context.Context) error {
+	if !i.roll(i.cfg.LatencyRate) {
+		return nil
+	}
+
+	select {
+	case <-time.After(i.cfg.Latency):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (i *Injector) HTTPError() bool {
+	return i.roll(i.cfg.HTTPErrorRate)
+}
+
+func (i *Injector) DBError() bool {
+	return i.roll(i.cfg.DBErrorRate)
+}
+
+func (i *Injector) DBDrop() bool {
+	return i.roll(i.cfg.DBDropRate)
+}
@@ -0,0 +1,127 @@
+package chaos
+
+import (
+	"context"
+	"database/sql/driver"
+)
+
+// WrapConnector decora as conexões do banco injetando latência, erros e
+// quedas de conexão (driver.ErrBadConn) antes de cada comando
+func (i *Injector) WrapConnector(base driver.Connector) driver.Connector {
+	return connector{base: base, injector: i}
+}
+
+type connector struct {
+	base     driver.Connector
+	injector *Injector
+}
+
+func (c connector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.base.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &chaosConn{Conn: conn, injector: c.injector}, nil
+}
+
+func (c connector) Driver() driver.Driver {
+	return c.base.Driver()
+}
+
+// repassa as interfaces opcionais da conexão original: sem ExecerContext/QueryerContext,
+// o database/sql passaria todo comando por PrepareContext, o que impede comandos com
+// várias instruções (as migrações) e custa uma ida ao banco a mais por consulta
+type chaosConn struct {
+	driver.Conn
+	injector *Injector
+}
+
+func (c *chaosConn) fault(ctx context.Context) error {
+	if err := c.injector.Delay(ctx); err != nil {
+		return err
+	}
+
+	if c.injector.DBDrop() {
+		c.Conn.Close()
+		return driver.ErrBadConn
+	}
+
+	if c.injector.DBError() {
+		return ErrInjected
+	}
+
+	return nil
+}
+
+func (c *chaosConn) Prepare(query string) (driver.Stmt, error) {
+	return c.PrepareContext(context.Background(), query)
+}
+
+func (c *chaosConn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	if err := c.fault(ctx); err != nil {
+		return nil, err
+	}
+
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		return preparer.PrepareContext(ctx, query)
+	}
+	return c.Conn.Prepare(query)
+}
+
+func (c *chaosConn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if err := c.fault(ctx); err != nil {
+		return nil, err
+	}
+
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+func (c *chaosConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	if err := c.fault(ctx); err != nil {
+		return nil, err
+	}
+	return execer.ExecContext(ctx, query, args)
+}
+
+func (c *chaosConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	if err := c.fault(ctx); err != nil {
+		return nil, err
+	}
+	return queryer.QueryContext(ctx, query, args)
+}
+
+func (c *chaosConn) Ping(ctx context.Context) error {
+	if err := c.fault(ctx); err != nil {
+		return err
+	}
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *chaosConn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *chaosConn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+	return true
+}
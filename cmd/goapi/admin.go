@@ -0,0 +1,213 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func newAdminCommand() *cobra.Command {
+	var baseURL, apiKey string
+	api := func() *client { return newClient(baseURL, apiKey) }
+
+	admin := &cobra.Command{
+		Use:   "admin",
+		Short: "Operações administrativas em uma API em execução",
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if apiKey == "" {
+				return errors.New("informe a chave com --api-key ou GOAPI_API_KEY")
+			}
+			return nil
+		},
+	}
+	admin.PersistentFlags().StringVar(&baseURL, "url", envOr("GOAPI_URL", "http://localhost:8080"), "endereço da API")
+	admin.PersistentFlags().StringVar(&apiKey, "api-key", os.Getenv("GOAPI_API_KEY"), "chave administrativa (X-API-Key)")
+
+	admin.AddCommand(newUsersCommand(api), newReindexCommand(api), newMaintenanceCommand(api), newTaskCommand(api),
+		newInvitationsCommand(api))
+	return admin
+}
+
+func newUsersCommand(api func() *client) *cobra.Command {
+	users := &cobra.Command{
+		Use:   "users",
+		Short: "Lista, cria e remove usuários",
+	}
+
+	var limit, offset int
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "Lista uma página de usuários",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := url.Values{}
+			query.Set("limit", strconv.Itoa(limit))
+			query.Set("offset", strconv.Itoa(offset))
+			return api().do(http.MethodGet, "/users?"+query.Encode(), nil)
+		},
+	}
+	list.Flags().IntVar(&limit, "limit", 50, "usuários por página")
+	list.Flags().IntVar(&offset, "offset", 0, "quantidade de usuários a pular")
+
+	var user struct {
+		Name     string `json:"name"`
+		Email    string `json:"email"`
+		ImgURL   string `json:"img_url,omitempty"`
+		Password string `json:"password,omitempty"`
+	}
+	create := &cobra.Command{
+		Use:   "create",
+		Short: "Cria um usuário",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return api().do(http.MethodPost, "/admin/users", user)
+		},
+	}
+	create.Flags().StringVar(&user.Name, "name", "", "nome")
+	create.Flags().StringVar(&user.Email, "email", "", "email")
+	create.Flags().StringVar(&user.ImgURL, "img-url", "", "URL da imagem")
+	create.Flags().StringVar(&user.Password, "password", "", "senha inicial")
+	create.MarkFlagRequired("name")
+	create.MarkFlagRequired("email")
+
+	remove := &cobra.Command{
+		Use:   "delete <id>",
+		Short: "Remove um usuário",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := strconv.Atoi(args[0]); err != nil {
+				return errors.New("o id do usuário deve ser numérico")
+			}
+			return api().do(http.MethodDelete, "/admin/users/"+args[0], nil)
+		},
+	}
+
+	users.AddCommand(list, create, remove)
+	return users
+}
+
+func newReindexCommand(api func() *client) *cobra.Command {
+	return &cobra.Command{
+		Use:   "reindex",
+		Short: "Enfileira a reconstrução dos índices da tabela de usuários",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return api().do(http.MethodPost, "/admin/reindex", nil)
+		},
+	}
+}
+
+func newTaskCommand(api func() *client) *cobra.Command {
+	return &cobra.Command{
+		Use:   "task <id>",
+		Short: "Mostra o status e o progresso de uma task",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := strconv.ParseInt(args[0], 10, 64); err != nil {
+				return errors.New("o id da task deve ser numérico")
+			}
+			return api().do(http.MethodGet, "/tasks/"+args[0], nil)
+		},
+	}
+}
+
+func newInvitationsCommand(api func() *client) *cobra.Command {
+	invitations := &cobra.Command{
+		Use:   "invitations",
+		Short: "Cria, lista e revoga convites de cadastro",
+	}
+
+	var limit, offset int
+	list := &cobra.Command{
+		Use:   "list",
+		Short: "Lista uma página de convites, com usos e status",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			query := url.Values{}
+			query.Set("limit", strconv.Itoa(limit))
+			query.Set("offset", strconv.Itoa(offset))
+			return api().do(http.MethodGet, "/admin/invitations?"+query.Encode(), nil)
+		},
+	}
+	list.Flags().IntVar(&limit, "limit", 50, "convites por página")
+	list.Flags().IntVar(&offset, "offset", 0, "quantidade de convites a pular")
+
+	var maxUses int
+	var expiresIn time.Duration
+	var note string
+	create := &cobra.Command{
+		Use:   "create",
+		Short: "Cria um convite e mostra o código, que não pode ser consultado depois",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			request := map[string]any{"max_uses": maxUses, "note": note}
+			if expiresIn > 0 {
+				request["expires_at"] = time.Now().Add(expiresIn).UTC().Format(time.RFC3339)
+			}
+			return api().do(http.MethodPost, "/admin/invitations", request)
+		},
+	}
+	create.Flags().IntVar(&maxUses, "max-uses", 1, "cadastros permitidos com o convite")
+	create.Flags().DurationVar(&expiresIn, "expires-in", 7*24*time.Hour, "validade; 0 não expira")
+	create.Flags().StringVar(&note, "note", "", "anotação para identificar o convite")
+
+	byID := func(use, short, method string) *cobra.Command {
+		return &cobra.Command{
+			Use:   use + " <id>",
+			Short: short,
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				if _, err := strconv.ParseInt(args[0], 10, 64); err != nil {
+					return errors.New("o id do convite deve ser numérico")
+				}
+				return api().do(method, "/admin/invitations/"+args[0], nil)
+			},
+		}
+	}
+
+	invitations.AddCommand(list, create,
+		byID("show", "Mostra o convite e os cadastros feitos com ele", http.MethodGet),
+		byID("revoke", "Revoga o convite", http.MethodDelete),
+	)
+	return invitations
+}
+
+func newMaintenanceCommand(api func() *client) *cobra.Command {
+	maintenance := &cobra.Command{
+		Use:   "maintenance",
+		Short: "Consulta ou alterna o modo de manutenção",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return api().do(http.MethodGet, "/admin/maintenance", nil)
+		},
+	}
+
+	toggle := func(use, short string, enabled bool) *cobra.Command {
+		return &cobra.Command{
+			Use:   use,
+			Short: short,
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return api().do(http.MethodPut, "/admin/maintenance", map[string]bool{"enabled": enabled})
+			},
+		}
+	}
+
+	maintenance.AddCommand(
+		toggle("on", "Liga o modo de manutenção", true),
+		toggle("off", "Desliga o modo de manutenção", false),
+	)
+	return maintenance
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
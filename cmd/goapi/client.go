@@ -0,0 +1,85 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// client fala com as rotas administrativas de uma API em execução
+type client struct {
+	baseURL string
+	apiKey  string
+	http    *http.Client
+}
+
+func newClient(baseURL, apiKey string) *client {
+	return &client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// do envia a requisição e escreve o corpo da resposta, formatado, na saída padrão.
+// Respostas fora da faixa 2xx viram erro com a mensagem devolvida pela API.
+func (c *client) do(method, path string, body any) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	request, err := http.NewRequest(method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	request.Header.Set("X-API-Key", c.apiKey)
+	if body != nil {
+		request.Header.Set("Content-Type", "application/json")
+	}
+
+	response, err := c.http.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		var apiErr struct {
+			Message string `json:"message"`
+			Error   string `json:"error"`
+		}
+		json.Unmarshal(content, &apiErr)
+		message := apiErr.Message + apiErr.Error
+		if message == "" {
+			message = strings.TrimSpace(string(content))
+		}
+		return fmt.Errorf("%s %s: %s: %s", method, path, response.Status, message)
+	}
+
+	if len(content) == 0 {
+		fmt.Fprintln(os.Stdout, response.Status)
+		return nil
+	}
+
+	var formatted bytes.Buffer
+	if err := json.Indent(&formatted, content, "", "  "); err != nil {
+		formatted.Write(content)
+	}
+	fmt.Fprintln(os.Stdout, formatted.String())
+	return nil
+}
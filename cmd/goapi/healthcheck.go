@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pytsx/goapi/model"
+	"github.com/spf13/cobra"
+)
+
+// newHealthcheckCommand consulta o /readyz (ou o /healthz com --live) da instância local e
+// termina com código 1 se ela não estiver pronta. Pensado para o HEALTHCHECK do contêiner,
+// dispensando curl ou wget na imagem
+func newHealthcheckCommand() *cobra.Command {
+	var baseURL string
+	var live bool
+	var timeout time.Duration
+
+	command := &cobra.Command{
+		Use:           "healthcheck",
+		Short:         "Verifica se a API local está pronta (código de saída 0 ou 1)",
+		Args:          cobra.NoArgs,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path := "/readyz"
+			if live {
+				path = "/healthz"
+			}
+
+			err := probe(strings.TrimRight(baseURL, "/")+path, timeout)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, err)
+			}
+			return err
+		},
+	}
+	command.Flags().StringVar(&baseURL, "url", envOr("GOAPI_URL", "http://localhost:8080"), "endereço da API")
+	command.Flags().BoolVar(&live, "live", false, "consulta o /healthz, que não verifica as dependências")
+	command.Flags().DurationVar(&timeout, "timeout", 5*time.Second, "prazo da requisição")
+	return command
+}
+
+func probe(url string, timeout time.Duration) error {
+	client := &http.Client{Timeout: timeout}
+	response, err := client.Get(url)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	content, _ := io.ReadAll(io.LimitReader(response.Body, 64<<10))
+	var report model.HealthReport
+	if json.Unmarshal(content, &report) != nil || len(report.Checks) == 0 {
+		return fmt.Errorf("%s: %s", url, response.Status)
+	}
+
+	var failed []string
+	for _, check := range report.Checks {
+		if check.Status != "ok" {
+			failed = append(failed, check.Name)
+		}
+	}
+	return fmt.Errorf("%s: %s (%s)", url, response.Status, strings.Join(failed, "; "))
+}
@@ -0,0 +1,20 @@
+package main
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+func main() {
+	root := &cobra.Command{
+		Use:          "goapi",
+		Short:        "Ferramentas de linha de comando da goapi",
+		SilenceUsage: true,
+	}
+	root.AddCommand(newAdminCommand(), newHealthcheckCommand())
+
+	if err := root.Execute(); err != nil {
+		os.Exit(1)
+	}
+}
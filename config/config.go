@@ -0,0 +1,276 @@
+package config
+
+import (
+	"time"
+
+	"github.com/pytsx/goapi/normalize"
+)
+
+type Config struct {
+	// development, staging ou production
+	Environment string
+
+	// chaves aceitas no header X-API-Key das rotas administrativas
+	AdminAPIKeys []string
+	// chaves de integrações confiáveis, dispensadas de CAPTCHA
+	TrustedAPIKeys []string
+
+	// diretório com as chaves de assinatura (<kid>.pem). Vazio gera uma chave efêmera no boot
+	JWTKeysDir  string
+	JWTTokenTTL time.Duration
+	// validade das sessões (refresh tokens)
+	RefreshTokenTTL time.Duration
+	// sessões abertas há menos que isso podem trocar a senha sem informar a atual
+	ReauthWindow time.Duration
+
+	// listener dedicado que exige certificado de cliente (mTLS). Vazio desativa
+	MTLSAddr         string
+	MTLSCertFile     string
+	MTLSKeyFile      string
+	MTLSClientCAFile string
+	// identidade do certificado (CN ou URI SAN) => conta de serviço
+	MTLSServiceAccounts map[string]string
+
+	// proxies (IPs ou CIDRs) cujo X-Forwarded-For é aceito como IP do cliente. Vazio usa
+	// sempre o IP da conexão, para o header não burlar as regras de IP, o GeoIP e a auditoria
+	TrustedProxies []string
+
+	// arquivo JSON com regras de CIDR (global e por grupo de rotas), recarregado ao ser alterado
+	IPRulesFile           string
+	IPRulesReloadInterval time.Duration
+
+	// base MaxMind (GeoIP2/GeoLite2 City). Vazio desativa a consulta
+	GeoIPDBPath           string
+	GeoIPBlockedCountries []string
+
+	// recaptcha, hcaptcha ou turnstile. Vazio desativa a verificação
+	CaptchaProvider string
+	CaptchaSecret   string
+
+	// servidor SMTP (host:porta). Vazio apenas registra os emails no log
+	SMTPAddr     string
+	SMTPUsername string
+	SMTPPassword string
+	SMTPFrom     string
+	// segredo dos webhooks de bounce/reclamação, no header X-Webhook-Token ou como senha do basic auth
+	EmailWebhookSecret string
+
+	// política => versão vigente, ex.: "terms=2024-06,privacy=3"
+	ConsentPolicies map[string]string
+	// bloqueia o uso da API até que as versões vigentes sejam aceitas
+	ConsentEnforce bool
+
+	// moderação do nome no cadastro: listas locais e, opcionalmente, um serviço externo
+	ModerationRejectedWords []string
+	ModerationFlaggedWords  []string
+	ModerationAPIURL        string
+	ModerationAPIKey        string
+
+	// avatares aprovados são servidos em AvatarBaseURL; reprovados ficam na quarentena
+	AvatarDir           string
+	AvatarBaseURL       string
+	AvatarQuarantineDir string
+	AvatarMaxBytes      int
+	// scanners de avatar: clamd (host:porta) e classificador NSFW. Vazios desativam
+	ClamAVAddr    string
+	NSFWAPIURL    string
+	NSFWAPIKey    string
+	NSFWThreshold float64
+
+	// arquivos gerados pelas tasks de exportação, baixados em /admin/exports
+	ExportDir string
+
+	// verificação das dependências no boot
+	StartupCheckAttempts int
+	StartupCheckBackoff  time.Duration
+	StartupCheckTimeout  time.Duration
+	// prazo padrão de cada verificação do /readyz e espaço livre mínimo nos diretórios de dados
+	HealthCheckTimeout  time.Duration
+	HealthMinFreeDiskMB int
+	// por quanto tempo o /readyz reaproveita o último resultado, para que chamadas
+	// seguidas não martelem as dependências
+	HealthCacheTTL time.Duration
+
+	// anexa o trace id do header traceparent como exemplar no histograma de latência do /metrics
+	MetricsExemplars bool
+
+	// chamadas a serviços externos (moderação, NSFW, CAPTCHA, confirmação do SNS): novas
+	// tentativas nas falhas transitórias e circuit breaker por integração
+	OutboundRetries          int
+	OutboundBackoff          time.Duration
+	OutboundBreakerThreshold int
+	OutboundBreakerCooldown  time.Duration
+
+	// injeção de falhas para testes de resiliência. Em production exige ChaosAllowProduction
+	ChaosEnabled         bool
+	ChaosAllowProduction bool
+	ChaosLatency         time.Duration
+	ChaosLatencyRate     float64
+	ChaosHTTPErrorRate   float64
+	ChaosDBErrorRate     float64
+	ChaosDBDropRate      float64
+
+	// prazo das consultas por classe de rota (leituras e escritas) e teto por comando no Postgres
+	DBReadTimeout      time.Duration
+	DBWriteTimeout     time.Duration
+	DBStatementTimeout time.Duration
+	// fail recusa subir se faltarem colunas ou índices esperados; warn apenas registra no log
+	SchemaDriftAction string
+
+	// máximo de requisições simultâneas por grupo de rotas (exports, avatars, duplicates) e
+	// quanto esperar por uma vaga antes de responder 503
+	Bulkheads            map[string]int
+	BulkheadQueueTimeout time.Duration
+
+	// worker de jobs assíncronos (tabela jobs): quantos workers consomem cada fila,
+	// para que uma task longa em "tasks" não atrase os emails de "default"
+	JobsQueues       map[string]int
+	JobsPollInterval time.Duration
+	JobsRetryBackoff time.Duration
+	// job em execução sem sinal de vida por mais que isso volta para a fila
+	// (réplica que caiu ou foi encerrada no meio do job)
+	JobsLease time.Duration
+
+	// prazo para concluir as requisições e o job em andamento após SIGTERM
+	ShutdownTimeout time.Duration
+
+	// limite de linhas do GET /users?paginate=false (streaming, apenas admins)
+	UsersStreamMaxRows int
+
+	// regras de normalização aplicadas aos dados do usuário antes da validação
+	NormalizeRules []string
+
+	// Redis compartilhado entre as instâncias (host:porta). Vazio mantém a presença em memória
+	RedisAddr     string
+	RedisPassword string
+	RedisDB       int
+	// tempo sem heartbeat até o usuário deixar de ser considerado online
+	PresenceTTL time.Duration
+
+	// cotas brandas: total de usuários e requisições por dia de cada chave confiável. Zero desativa
+	QuotaMaxUsers      int
+	QuotaDailyRequests int
+
+	// cadastro público apenas com convite; os links apontam para InviteLinkBase?invite=<código>
+	InviteOnly     bool
+	InviteLinkBase string
+
+	// intervalo com que cada instância busca a última revisão da configuração de execução
+	// (importações e modo de manutenção gravados por outra instância)
+	RuntimeConfigPollInterval time.Duration
+}
+
+func Load() Config {
+	return Config{
+		Environment: getEnv("APP_ENV", "development"),
+
+		AdminAPIKeys:    getEnvList("ADMIN_API_KEYS"),
+		TrustedAPIKeys:  getEnvList("TRUSTED_API_KEYS"),
+		JWTKeysDir:      getEnv("JWT_KEYS_DIR", ""),
+		JWTTokenTTL:     getEnvDuration("JWT_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL: getEnvDuration("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+		ReauthWindow:    getEnvDuration("REAUTH_WINDOW", 5*time.Minute),
+
+		MTLSAddr:            getEnv("MTLS_ADDR", ""),
+		MTLSCertFile:        getEnv("MTLS_CERT_FILE", ""),
+		MTLSKeyFile:         getEnv("MTLS_KEY_FILE", ""),
+		MTLSClientCAFile:    getEnv("MTLS_CLIENT_CA_FILE", ""),
+		MTLSServiceAccounts: getEnvMap("MTLS_SERVICE_ACCOUNTS"),
+
+		TrustedProxies: getEnvList("TRUSTED_PROXIES"),
+
+		IPRulesFile:           getEnv("IP_RULES_FILE", ""),
+		IPRulesReloadInterval: getEnvDuration("IP_RULES_RELOAD_INTERVAL", 10*time.Second),
+
+		GeoIPDBPath:           getEnv("GEOIP_DB_PATH", ""),
+		GeoIPBlockedCountries: getEnvList("GEOIP_BLOCKED_COUNTRIES"),
+
+		CaptchaProvider: getEnv("CAPTCHA_PROVIDER", ""),
+		CaptchaSecret:   getEnv("CAPTCHA_SECRET", ""),
+
+		SMTPAddr:     getEnv("SMTP_ADDR", ""),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:     getEnv("SMTP_FROM", "no-reply@goapi.local"),
+
+		EmailWebhookSecret: getEnv("EMAIL_WEBHOOK_SECRET", ""),
+
+		ConsentPolicies: getEnvMap("CONSENT_POLICIES"),
+		ConsentEnforce:  getEnvBool("CONSENT_ENFORCE", false),
+
+		ModerationRejectedWords: getEnvList("MODERATION_REJECTED_WORDS"),
+		ModerationFlaggedWords:  getEnvList("MODERATION_FLAGGED_WORDS"),
+		ModerationAPIURL:        getEnv("MODERATION_API_URL", ""),
+		ModerationAPIKey:        getEnv("MODERATION_API_KEY", ""),
+
+		AvatarDir:           getEnv("AVATAR_DIR", "data/avatars"),
+		AvatarBaseURL:       getEnv("AVATAR_BASE_URL", "/avatars"),
+		AvatarQuarantineDir: getEnv("AVATAR_QUARANTINE_DIR", "data/quarantine"),
+		AvatarMaxBytes:      getEnvInt("AVATAR_MAX_BYTES", 5<<20),
+		ClamAVAddr:          getEnv("CLAMAV_ADDR", ""),
+		NSFWAPIURL:          getEnv("NSFW_API_URL", ""),
+		NSFWAPIKey:          getEnv("NSFW_API_KEY", ""),
+		NSFWThreshold:       getEnvFloat("NSFW_THRESHOLD", 0.8),
+
+		ExportDir: getEnv("EXPORT_DIR", "data/exports"),
+
+		StartupCheckAttempts: getEnvInt("STARTUP_CHECK_ATTEMPTS", 5),
+		StartupCheckBackoff:  getEnvDuration("STARTUP_CHECK_BACKOFF", time.Second),
+		StartupCheckTimeout:  getEnvDuration("STARTUP_CHECK_TIMEOUT", 5*time.Second),
+		HealthCheckTimeout:   getEnvDuration("HEALTH_CHECK_TIMEOUT", 2*time.Second),
+		HealthMinFreeDiskMB:  getEnvInt("HEALTH_MIN_FREE_DISK_MB", 100),
+		HealthCacheTTL:       getEnvDuration("HEALTH_CACHE_TTL", 5*time.Second),
+
+		MetricsExemplars: getEnvBool("METRICS_EXEMPLARS", false),
+
+		OutboundRetries:          getEnvInt("OUTBOUND_RETRIES", 2),
+		OutboundBackoff:          getEnvDuration("OUTBOUND_BACKOFF", 200*time.Millisecond),
+		OutboundBreakerThreshold: getEnvInt("OUTBOUND_BREAKER_THRESHOLD", 5),
+		OutboundBreakerCooldown:  getEnvDuration("OUTBOUND_BREAKER_COOLDOWN", 30*time.Second),
+
+		ChaosEnabled:         getEnvBool("CHAOS_ENABLED", false),
+		ChaosAllowProduction: getEnvBool("CHAOS_ALLOW_PRODUCTION", false),
+		ChaosLatency:         getEnvDuration("CHAOS_LATENCY", 2*time.Second),
+		ChaosLatencyRate:     getEnvFloat("CHAOS_LATENCY_RATE", 0),
+		ChaosHTTPErrorRate:   getEnvFloat("CHAOS_HTTP_ERROR_RATE", 0),
+		ChaosDBErrorRate:     getEnvFloat("CHAOS_DB_ERROR_RATE", 0),
+		ChaosDBDropRate:      getEnvFloat("CHAOS_DB_DROP_RATE", 0),
+
+		DBReadTimeout:      getEnvDuration("DB_READ_TIMEOUT", 2*time.Second),
+		DBWriteTimeout:     getEnvDuration("DB_WRITE_TIMEOUT", 5*time.Second),
+		DBStatementTimeout: getEnvDuration("DB_STATEMENT_TIMEOUT", time.Minute),
+		SchemaDriftAction:  getEnv("SCHEMA_DRIFT_ACTION", "fail"),
+
+		Bulkheads:            getEnvIntMap("BULKHEADS", map[string]int{"exports": 2, "avatars": 4, "duplicates": 8}),
+		BulkheadQueueTimeout: getEnvDuration("BULKHEAD_QUEUE_TIMEOUT", 500*time.Millisecond),
+
+		JobsQueues:       getEnvIntMap("JOBS_QUEUES", map[string]int{"default": 2, "tasks": 1}),
+		JobsPollInterval: getEnvDuration("JOBS_POLL_INTERVAL", time.Second),
+		JobsRetryBackoff: getEnvDuration("JOBS_RETRY_BACKOFF", 30*time.Second),
+		JobsLease:        getEnvDuration("JOBS_LEASE", 5*time.Minute),
+
+		ShutdownTimeout: getEnvDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
+
+		UsersStreamMaxRows: getEnvInt("USERS_STREAM_MAX_ROWS", 1000000),
+
+		NormalizeRules: getEnvListOr("NORMALIZE_RULES", normalize.DefaultRules),
+
+		RedisAddr:     getEnv("REDIS_ADDR", ""),
+		RedisPassword: getEnv("REDIS_PASSWORD", ""),
+		RedisDB:       getEnvInt("REDIS_DB", 0),
+		PresenceTTL:   getEnvDuration("PRESENCE_TTL", time.Minute),
+
+		QuotaMaxUsers:      getEnvInt("QUOTA_MAX_USERS", 0),
+		QuotaDailyRequests: getEnvInt("QUOTA_DAILY_REQUESTS", 0),
+
+		InviteOnly:     getEnvBool("INVITE_ONLY", false),
+		InviteLinkBase: getEnv("INVITE_LINK_BASE", ""),
+
+		RuntimeConfigPollInterval: getEnvDuration("RUNTIME_CONFIG_POLL_INTERVAL", 5*time.Second),
+	}
+}
+
+// ChaosActive indica se a injeção de falhas deve ser ligada neste ambiente
+func (c Config) ChaosActive() bool {
+	return c.ChaosEnabled && (c.Environment != "production" || c.ChaosAllowProduction)
+}
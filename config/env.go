@@ -0,0 +1,93 @@
+package config
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func getEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(getEnv(key, ""))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// lê uma lista separada por vírgulas, ignorando itens vazios
+func getEnvList(key string) []string {
+	var list []string
+	for _, item := range strings.Split(getEnv(key, ""), ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// lê pares chave=valor separados por vírgulas
+func getEnvMap(key string) map[string]string {
+	pairs := map[string]string{}
+	for _, item := range getEnvList(key) {
+		name, value, ok := strings.Cut(item, "=")
+		if ok {
+			pairs[strings.TrimSpace(name)] = strings.TrimSpace(value)
+		}
+	}
+	return pairs
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	value, err := strconv.ParseBool(getEnv(key, ""))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func getEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(getEnv(key, ""))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func getEnvFloat(key string, fallback float64) float64 {
+	value, err := strconv.ParseFloat(getEnv(key, ""), 64)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func getEnvListOr(key string, fallback []string) []string {
+	if list := getEnvList(key); len(list) > 0 {
+		return list
+	}
+	return fallback
+}
+
+// lê pares nome=inteiro; sem a variável, usa fallback. Valores inválidos são ignorados
+func getEnvIntMap(key string, fallback map[string]int) map[string]int {
+	if getEnv(key, "") == "" {
+		return fallback
+	}
+
+	values := map[string]int{}
+	for name, raw := range getEnvMap(key) {
+		if value, err := strconv.Atoi(raw); err == nil {
+			values[name] = value
+		}
+	}
+	return values
+}
@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/geoip"
+	"github.com/pytsx/goapi/i18n"
+	"github.com/pytsx/goapi/mask"
+	"github.com/pytsx/goapi/middleware"
+	"github.com/pytsx/goapi/model"
+)
+
+// actorFromRequest identifica quem fez a requisição para a trilha de auditoria
+func actorFromRequest(ctx *gin.Context) model.Actor {
+	actor := model.Actor{
+		ID: "anonymous",
+		IP: ctx.ClientIP(),
+	}
+
+	if claims, ok := middleware.Claims(ctx); ok {
+		actor.ID = "user:" + claims.Subject
+	} else if account, ok := middleware.ServiceAccount(ctx); ok {
+		actor.ID = "service:" + account
+	} else if ctx.GetHeader(middleware.APIKeyHeader) != "" {
+		actor.ID = "api_key"
+	}
+
+	if location, ok := geoip.FromContext(ctx.Request.Context()); ok {
+		actor.Country = location.Country
+	}
+
+	return actor
+}
+
+// viewerFromRequest define quais campos mascarados o chamador pode ver:
+// admins e contas de serviço veem tudo, usuários autenticados veem os próprios dados
+func viewerFromRequest(ctx *gin.Context) mask.Viewer {
+	viewer := mask.Viewer{
+		Privileged: middleware.IsAdmin(ctx),
+	}
+
+	if _, ok := middleware.ServiceAccount(ctx); ok {
+		viewer.Privileged = true
+	}
+	if claims, ok := middleware.Claims(ctx); ok {
+		viewer.UserID, _ = strconv.Atoi(claims.Subject)
+	}
+
+	return viewer
+}
+
+// localeFromRequest escolhe o idioma do conteúdo gerado a partir do Accept-Language
+func localeFromRequest(ctx *gin.Context) string {
+	locale := i18n.Match(ctx.GetHeader("Accept-Language"))
+	ctx.Header("Content-Language", locale)
+	return locale
+}
@@ -0,0 +1,95 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/geoip"
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/usecase"
+)
+
+type AuthController struct {
+	authUsecase usecase.AuthUsecase
+}
+
+func NewAuthController(usecase usecase.AuthUsecase) AuthController {
+	return AuthController{
+		authUsecase: usecase,
+	}
+}
+
+func (ac *AuthController) Login(ctx *gin.Context) {
+	var credentials model.Credentials
+	if err := ctx.ShouldBindBodyWithJSON(&credentials); err != nil {
+		response := model.Response{
+			Message: "Essa rota espera receber email e senha",
+			Details: bindingDetails(ctx, err, &credentials),
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	token, err := ac.authUsecase.Login(credentials, loginFromRequest(ctx))
+	if err == usecase.ErrInvalidCredentials {
+		response := model.Response{
+			Message: "Email ou senha incorretos",
+		}
+		ctx.JSON(http.StatusUnauthorized, response)
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, token)
+}
+
+func (ac *AuthController) Refresh(ctx *gin.Context) {
+	var request model.RefreshRequest
+	if err := ctx.ShouldBindBodyWithJSON(&request); err != nil {
+		response := model.Response{
+			Message: "Essa rota espera receber um refresh_token",
+			Details: bindingDetails(ctx, err, &request),
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	token, err := ac.authUsecase.Refresh(request.RefreshToken, ctx.ClientIP())
+	if err == usecase.ErrInvalidRefreshToken {
+		response := model.Response{
+			Message: "Sessão inválida, expirada ou revogada",
+		}
+		ctx.JSON(http.StatusUnauthorized, response)
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, token)
+}
+
+// identifica o dispositivo (X-Device-ID ou User-Agent) e a origem do acesso
+func loginFromRequest(ctx *gin.Context) model.Login {
+	login := model.Login{
+		Device:   ctx.GetHeader("X-Device-ID"),
+		IP:       ctx.ClientIP(),
+		Location: ctx.ClientIP(),
+	}
+
+	if login.Device == "" {
+		login.Device = ctx.Request.UserAgent()
+	}
+
+	if location, ok := geoip.FromContext(ctx.Request.Context()); ok {
+		login.Country = location.Country
+		login.City = location.City
+		login.Location = location.Country + "/" + location.City
+	}
+
+	return login
+}
@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/usecase"
+)
+
+type AvatarController struct {
+	avatarUsecase usecase.AvatarUsecase
+	maxBytes      int64
+}
+
+func NewAvatarController(usecase usecase.AvatarUsecase, maxBytes int64) AvatarController {
+	return AvatarController{
+		avatarUsecase: usecase,
+		maxBytes:      maxBytes,
+	}
+}
+
+// UploadAvatar recebe a imagem no campo multipart `avatar`
+func (ac *AvatarController) UploadAvatar(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response := model.Response{
+			Message: "Essa rota espera receber um id numérico",
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, ac.maxBytes)
+
+	fileHeader, err := ctx.FormFile("avatar")
+	if err != nil {
+		response := model.Response{
+			Message: "Envie a imagem no campo avatar, respeitando o tamanho máximo",
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	defer file.Close()
+
+	content, err := io.ReadAll(file)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	user, err := ac.avatarUsecase.UploadAvatar(ctx.Request.Context(), userID, content)
+	switch {
+	case err == usecase.ErrUnsupportedImage:
+		response := model.Response{
+			Message: "O avatar deve ser uma imagem PNG, JPEG, GIF ou WebP",
+		}
+		ctx.JSON(http.StatusUnsupportedMediaType, response)
+		return
+	case err == usecase.ErrAvatarRejected:
+		response := model.Response{
+			Message: "A imagem foi reprovada na verificação de conteúdo",
+		}
+		ctx.JSON(http.StatusUnprocessableEntity, response)
+		return
+	case err != nil:
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	case user == nil:
+		response := model.Response{
+			Message: "Nenhum usuário foi localizado com o id fornecido",
+		}
+		ctx.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, user)
+}
@@ -0,0 +1,90 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// bindingDetails aponta o campo que não pôde ser decodificado. Erros de sintaxe ou de
+// corpo vazio não têm detalhes e resultam em nil. target é o valor passado ao
+// ShouldBindBodyWithJSON, que guarda o corpo no contexto para a busca do campo
+func bindingDetails(ctx *gin.Context, err error, target any) map[string]string {
+	var typeErr *json.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		return nil
+	}
+	// o encoding/json não completa o campo nos erros devolvidos por UnmarshalJSON, caso
+	// dos tipos do model; o campo é procurado no corpo entre os que têm o tipo do erro
+	field := typeErr.Field
+	if field == "" {
+		if body, ok := ctx.Get(gin.BodyBytesKey); ok {
+			field = invalidField(body.([]byte), reflect.TypeOf(target), typeErr)
+		}
+	}
+	if field == "" {
+		field = "body"
+	}
+
+	// os tipos do model já descrevem o valor esperado; os primitivos só informam o tipo
+	detail := "valor inválido: " + typeErr.Value
+	if typeErr.Type != nil && typeErr.Type.PkgPath() == "" {
+		detail = "esperado " + typeErr.Type.String() + ", recebido " + typeErr.Value
+	}
+	return map[string]string{field: detail}
+}
+
+// invalidField decodifica de novo, um a um, os campos do corpo declarados em t com o tipo
+// do erro e devolve o nome do primeiro que falha. Listas indicam a posição do item.
+func invalidField(body []byte, t reflect.Type, typeErr *json.UnmarshalTypeError) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array:
+		var items []json.RawMessage
+		if json.Unmarshal(body, &items) != nil {
+			return ""
+		}
+		for i, item := range items {
+			if field := invalidField(item, t.Elem(), typeErr); field != "" {
+				return "[" + strconv.Itoa(i) + "]." + field
+			}
+		}
+	case reflect.Struct:
+		var members map[string]json.RawMessage
+		if json.Unmarshal(body, &members) != nil {
+			return ""
+		}
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			fieldType := field.Type
+			for fieldType.Kind() == reflect.Pointer {
+				fieldType = fieldType.Elem()
+			}
+			if !field.IsExported() || fieldType != typeErr.Type {
+				continue
+			}
+
+			name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = field.Name
+			}
+			// o encoding/json também aceita a chave com outra caixa
+			for key, raw := range members {
+				if strings.EqualFold(key, name) && json.Unmarshal(raw, reflect.New(fieldType).Interface()) != nil {
+					return name
+				}
+			}
+		}
+	}
+	return ""
+}
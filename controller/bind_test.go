@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/model"
+)
+
+func TestBindingDetails(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type review struct {
+		Action model.ModerationAction `json:"action"`
+		Reason model.NullString       `json:"reason"`
+		Note   model.NullString       `json:"note"`
+		Count  int                    `json:"count"`
+	}
+
+	tests := []struct {
+		name, body string
+		target     func() any
+		want       string
+	}{
+		{"campo de tipo do model", `{"reason":"ok","note":5}`, func() any { return &review{} }, "note"},
+		{"enum", `{"action":"talvez"}`, func() any { return &review{} }, "action"},
+		{"primitivo", `{"count":"dez"}`, func() any { return &review{} }, "count"},
+		{"chave com outra caixa", `{"Reason":true}`, func() any { return &review{} }, "reason"},
+		{"item de lista", `[{"note":"a"},{"note":[1]}]`, func() any { return &[]review{} }, "[1].note"},
+		{"corpo de outro tipo", `"texto"`, func() any { return &review{} }, "body"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+			ctx.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+
+			target := tt.target()
+			err := ctx.ShouldBindBodyWithJSON(target)
+			if err == nil {
+				t.Fatalf("ShouldBindBodyWithJSON(%s) não falhou", tt.body)
+			}
+			details := bindingDetails(ctx, err, target)
+			if _, ok := details[tt.want]; !ok || len(details) != 1 {
+				t.Errorf("bindingDetails(%s) = %v, esperado o campo %q", tt.body, details, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/usecase"
+)
+
+type ConfigController struct {
+	configUsecase usecase.ConfigUsecase
+}
+
+func NewConfigController(usecase usecase.ConfigUsecase) ConfigController {
+	return ConfigController{
+		configUsecase: usecase,
+	}
+}
+
+func (cc *ConfigController) Export(ctx *gin.Context) {
+	config, err := cc.configUsecase.Export()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Header("Content-Disposition", `attachment; filename="goapi-config.json"`)
+	ctx.Header("ETag", strconv.Quote(strconv.FormatInt(config.Revision, 10)))
+	ctx.JSON(http.StatusOK, config)
+}
+
+// Import exige If-Match com a revisão vigente deste ambiente (o ETag do export), para que
+// uma importação antiga não sobrescreva alterações feitas depois dela. O revision do
+// documento é só a origem (pode vir de outro ambiente); If-Match: * importa sem conferir
+func (cc *ConfigController) Import(ctx *gin.Context) {
+	expected, ok := expectedRevision(ctx.GetHeader("If-Match"))
+	if !ok {
+		response := model.Response{
+			Message: "Informe no header If-Match a revisão vigente (ETag do export) ou * para sobrescrever",
+		}
+		ctx.JSON(http.StatusPreconditionRequired, response)
+		return
+	}
+
+	var config model.RuntimeConfig
+
+	// campos desconhecidos indicam um documento de outra versão ou com erro de digitação
+	decoder := json.NewDecoder(ctx.Request.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		response := model.Response{
+			Message: "Documento de configuração inválido: " + err.Error(),
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	imported, err := cc.configUsecase.Import(config, expected, actorFromRequest(ctx))
+
+	var validationErr usecase.ValidationError
+	if errors.As(err, &validationErr) {
+		response := model.Response{
+			Message: "O documento de configuração é inválido",
+			Details: validationErr.Fields,
+		}
+		ctx.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	if err == usecase.ErrConfigConflict {
+		response := model.Response{
+			Message: "A configuração mudou desde a exportação; exporte novamente e reaplique as alterações",
+		}
+		ctx.JSON(http.StatusPreconditionFailed, response)
+		return
+	}
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, imported)
+}
+
+// expectedRevision lê o If-Match: nil para "*", a revisão para "<n>" (com ou sem aspas);
+// false quando ausente ou inválido
+func expectedRevision(ifMatch string) (*int64, bool) {
+	ifMatch = strings.TrimSpace(ifMatch)
+	if ifMatch == "*" {
+		return nil, true
+	}
+
+	revision, err := strconv.ParseInt(strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`), 10, 64)
+	if err != nil {
+		return nil, false
+	}
+	return &revision, true
+}
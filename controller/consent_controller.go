@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/usecase"
+)
+
+type ConsentController struct {
+	consentUsecase usecase.ConsentUsecase
+}
+
+func NewConsentController(usecase usecase.ConsentUsecase) ConsentController {
+	return ConsentController{
+		consentUsecase: usecase,
+	}
+}
+
+func (cc *ConsentController) GetPendingConsents(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response := model.Response{
+			Message: "Essa rota espera receber um id numérico",
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	pending, err := cc.consentUsecase.PendingConsents(userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pending)
+}
+
+func (cc *ConsentController) AcceptConsent(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response := model.Response{
+			Message: "Essa rota espera receber um id numérico",
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var consent model.Consent
+	if err := ctx.ShouldBindBodyWithJSON(&consent); err != nil {
+		response := model.Response{
+			Message: "Essa rota espera receber a política e a versão aceitas",
+			Details: bindingDetails(ctx, err, &consent),
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	err = cc.consentUsecase.AcceptConsent(userID, consent, ctx.ClientIP())
+	if err == usecase.ErrOutdatedPolicyVersion {
+		response := model.Response{
+			Message: "Apenas a versão vigente da política pode ser aceita",
+		}
+		ctx.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/health"
+	"github.com/pytsx/goapi/model"
+)
+
+type HealthController struct {
+	registry *health.Registry
+	cacheTTL time.Duration
+}
+
+func NewHealthController(registry *health.Registry, cacheTTL time.Duration) HealthController {
+	return HealthController{
+		registry: registry,
+		cacheTTL: cacheTTL,
+	}
+}
+
+// Live só indica que o processo responde; não consulta dependências
+func (hc *HealthController) Live(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// Ready executa as verificações registradas e responde 503 se alguma obrigatória falhar;
+// falhas das dependências opcionais só deixam o status como degraded. O erro e a dica
+// vão apenas para o log: o corpo não expõe endereços nem detalhes da infraestrutura
+func (hc *HealthController) Ready(ctx *gin.Context) {
+	report := hc.registry.RunCached(ctx.Request.Context(), hc.cacheTTL)
+
+	response := model.HealthReport{
+		Status: "ok",
+		Checks: make([]model.HealthCheck, 0, len(report)),
+	}
+
+	failing := map[string]bool{}
+	for _, result := range report {
+		check := model.HealthCheck{
+			Name:      result.Name,
+			Status:    "ok",
+			LatencyMS: float64(result.Duration) / float64(time.Millisecond),
+		}
+		if result.Err != nil {
+			failing[result.Name] = true
+			check.Status = "fail"
+			if !result.Optional {
+				response.Status = "fail"
+			}
+		}
+		response.Checks = append(response.Checks, check)
+	}
+
+	for _, capability := range hc.registry.Capabilities() {
+		item := model.HealthCapability{
+			Name:     capability.Name,
+			Status:   "enabled",
+			Features: capability.Features,
+		}
+		switch {
+		case !capability.Enabled:
+			item.Status = "disabled"
+			item.Fallback = capability.Fallback
+		case failing[capability.CheckName()]:
+			item.Status = "degraded"
+			if response.Status == "ok" {
+				response.Status = "degraded"
+			}
+		}
+		response.Capabilities = append(response.Capabilities, item)
+	}
+
+	status := http.StatusOK
+	if report.Failed() {
+		status = http.StatusServiceUnavailable
+	}
+	ctx.JSON(status, response)
+}
@@ -0,0 +1,151 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/middleware"
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/usecase"
+)
+
+type InvitationController struct {
+	invitationUsecase usecase.InvitationUsecase
+}
+
+func NewInvitationController(usecase usecase.InvitationUsecase) InvitationController {
+	return InvitationController{
+		invitationUsecase: usecase,
+	}
+}
+
+// Create devolve o código e o link do convite; eles não podem ser consultados depois
+func (ic *InvitationController) Create(ctx *gin.Context) {
+	var request model.InvitationRequest
+	if err := ctx.ShouldBindBodyWithJSON(&request); err != nil {
+		response := model.Response{
+			Message: "O corpo deve ser {\"max_uses\": ..., \"expires_at\": ..., \"note\": ...}",
+			Details: bindingDetails(ctx, err, &request),
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	invitations := ic.invitationUsecase.WithContext(middleware.DBContext(ctx))
+	invitation, err := invitations.Create(request, actorFromRequest(ctx))
+
+	var validationErr usecase.ValidationError
+	if errors.As(err, &validationErr) {
+		response := model.Response{
+			Message: "Os dados do convite são inválidos",
+			Details: validationErr.Fields,
+		}
+		ctx.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Header("Location", "/admin/invitations/"+strconv.FormatInt(invitation.ID, 10))
+	ctx.JSON(http.StatusCreated, invitation)
+}
+
+// List é paginado por ?limit= e ?offset=, com os convites mais recentes primeiro
+func (ic *InvitationController) List(ctx *gin.Context) {
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", strconv.Itoa(defaultPageSize)))
+	if err != nil || limit < 1 || limit > maxPageSize {
+		response := model.Response{
+			Message: "O parâmetro limit deve estar entre 1 e " + strconv.Itoa(maxPageSize),
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	offset, err := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		response := model.Response{
+			Message: "O parâmetro offset deve ser um número não negativo",
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	invitations := ic.invitationUsecase.WithContext(middleware.DBContext(ctx))
+	list, err := invitations.List(limit, offset)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, list)
+}
+
+// Get devolve o convite com os cadastros feitos a partir dele
+func (ic *InvitationController) Get(ctx *gin.Context) {
+	id, ok := invitationID(ctx)
+	if !ok {
+		return
+	}
+
+	invitations := ic.invitationUsecase.WithContext(middleware.DBContext(ctx))
+	invitation, uses, err := invitations.GetInvitation(id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if invitation == nil {
+		response := model.Response{
+			Message: "Nenhum convite foi localizado com o id fornecido",
+		}
+		ctx.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, model.InvitationReport{
+		Invitation: *invitation,
+		UsedBy:     uses,
+	})
+}
+
+// Revoke impede novos cadastros com o convite; os já feitos não são afetados
+func (ic *InvitationController) Revoke(ctx *gin.Context) {
+	id, ok := invitationID(ctx)
+	if !ok {
+		return
+	}
+
+	invitations := ic.invitationUsecase.WithContext(middleware.DBContext(ctx))
+	found, err := invitations.Revoke(id, actorFromRequest(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !found {
+		response := model.Response{
+			Message: "Nenhum convite foi localizado com o id fornecido",
+		}
+		ctx.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func invitationID(ctx *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response := model.Response{
+			Message: "Essa rota espera receber um id numérico",
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return 0, false
+	}
+	return id, true
+}
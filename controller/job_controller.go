@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/usecase"
+)
+
+type JobController struct {
+	jobUsecase usecase.JobUsecase
+}
+
+func NewJobController(usecase usecase.JobUsecase) JobController {
+	return JobController{
+		jobUsecase: usecase,
+	}
+}
+
+func (jc *JobController) GetQueues(ctx *gin.Context) {
+	queues, err := jc.jobUsecase.GetQueues()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, queues)
+}
+
+// GetJobs aceita ?queue=, ?kind= e ?status= (ex.: status=dead para a fila morta), paginados por ?limit= e ?offset=
+func (jc *JobController) GetJobs(ctx *gin.Context) {
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", strconv.Itoa(defaultPageSize)))
+	if err != nil || limit < 1 || limit > maxPageSize {
+		response := model.Response{
+			Message: "O parâmetro limit deve estar entre 1 e " + strconv.Itoa(maxPageSize),
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	offset, err := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		response := model.Response{
+			Message: "O parâmetro offset deve ser um número não negativo",
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	filter := model.JobFilter{
+		Queue:  ctx.Query("queue"),
+		Kind:   ctx.Query("kind"),
+		Status: ctx.Query("status"),
+	}
+
+	jobs, err := jc.jobUsecase.GetJobs(filter, limit, offset)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, jobs)
+}
+
+func (jc *JobController) GetJob(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response := model.Response{
+			Message: "Essa rota espera receber um id numérico",
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	job, err := jc.jobUsecase.GetJob(id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if job == nil {
+		response := model.Response{
+			Message: "Nenhum job foi localizado com o id fornecido",
+		}
+		ctx.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, job)
+}
+
+func (jc *JobController) RetryJob(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response := model.Response{
+			Message: "Essa rota espera receber um id numérico",
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	err = jc.jobUsecase.RetryDead(id, actorFromRequest(ctx))
+	if err == usecase.ErrJobNotDead {
+		response := model.Response{
+			Message: "Apenas jobs na fila morta podem ser reprocessados",
+		}
+		ctx.JSON(http.StatusConflict, response)
+		return
+	}
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusAccepted)
+}
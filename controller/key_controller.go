@@ -0,0 +1,36 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/auth"
+	"github.com/pytsx/goapi/model"
+)
+
+type KeyController struct {
+	keySet *auth.KeySet
+}
+
+func NewKeyController(keySet *auth.KeySet) KeyController {
+	return KeyController{
+		keySet: keySet,
+	}
+}
+
+func (kc *KeyController) JWKS(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, kc.keySet.JWKS())
+}
+
+func (kc *KeyController) Rotate(ctx *gin.Context) {
+	key, err := kc.keySet.Rotate()
+	if err != nil {
+		response := model.Response{
+			Message: "Não foi possível rotacionar a chave de assinatura",
+		}
+		ctx.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, gin.H{"kid": key.ID})
+}
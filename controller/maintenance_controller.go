@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/middleware"
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/usecase"
+)
+
+type MaintenanceController struct {
+	maintenanceUsecase usecase.MaintenanceUsecase
+	configUsecase      usecase.ConfigUsecase
+	mode               *middleware.MaintenanceMode
+}
+
+func NewMaintenanceController(usecase usecase.MaintenanceUsecase, configUsecase usecase.ConfigUsecase,
+	mode *middleware.MaintenanceMode) MaintenanceController {
+	return MaintenanceController{
+		maintenanceUsecase: usecase,
+		configUsecase:      configUsecase,
+		mode:               mode,
+	}
+}
+
+func (mc *MaintenanceController) GetMode(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, model.MaintenanceStatus{Enabled: mc.mode.Enabled()})
+}
+
+func (mc *MaintenanceController) SetMode(ctx *gin.Context) {
+	var request struct {
+		Enabled *bool `json:"enabled" binding:"required"`
+	}
+	if err := ctx.ShouldBindBodyWithJSON(&request); err != nil {
+		response := model.Response{
+			Message: "O corpo deve ser {\"enabled\": true|false}",
+			Details: bindingDetails(ctx, err, &request),
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// gravado na configuração de execução, que as demais instâncias acompanham
+	if err := mc.configUsecase.SetMaintenance(*request.Enabled, actorFromRequest(ctx)); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	ctx.JSON(http.StatusOK, model.MaintenanceStatus{Enabled: *request.Enabled})
+}
+
+func (mc *MaintenanceController) Reindex(ctx *gin.Context) {
+	task, err := mc.maintenanceUsecase.RequestReindex(actorFromRequest(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	accepted(ctx, task)
+}
@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/i18n"
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/usecase"
+)
+
+type ModerationController struct {
+	moderationUsecase usecase.ModerationUsecase
+}
+
+func NewModerationController(usecase usecase.ModerationUsecase) ModerationController {
+	return ModerationController{
+		moderationUsecase: usecase,
+	}
+}
+
+func (mc *ModerationController) GetFlagged(ctx *gin.Context) {
+	cases, err := mc.moderationUsecase.GetFlagged()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	locale := localeFromRequest(ctx)
+	for i := range cases {
+		cases[i].StatusLabel = i18n.T(locale, "moderation.status."+string(cases[i].Status))
+	}
+
+	renderJSON(ctx, http.StatusOK, cases)
+}
+
+func (mc *ModerationController) Review(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response := model.Response{
+			Message: "Essa rota espera receber um id numérico",
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var review model.ModerationReview
+	if err := ctx.ShouldBindBodyWithJSON(&review); err != nil {
+		response := model.Response{
+			Message: "A ação deve ser approve ou reject",
+			Details: bindingDetails(ctx, err, &review),
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	found, err := mc.moderationUsecase.Review(userID, review)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !found {
+		response := model.Response{
+			Message: "Nenhum usuário foi localizado com o id fornecido",
+		}
+		ctx.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (mc *ModerationController) GetUser(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response := model.Response{
+			Message: "Essa rota espera receber um id numérico",
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	user, err := mc.moderationUsecase.GetAdminUser(userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if user == nil {
+		response := model.Response{
+			Message: "Nenhum usuário foi localizado com o id fornecido",
+		}
+		ctx.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	locale := localeFromRequest(ctx)
+	user.Moderation.NameStatusLabel = i18n.T(locale, "moderation.status."+string(user.Moderation.NameStatus))
+	user.Moderation.AvatarStatusLabel = i18n.T(locale, "avatar.status."+user.Moderation.AvatarStatus)
+
+	ctx.JSON(http.StatusOK, user)
+}
@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/middleware"
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/usecase"
+)
+
+type PasswordController struct {
+	passwordUsecase usecase.PasswordUsecase
+}
+
+func NewPasswordController(usecase usecase.PasswordUsecase) PasswordController {
+	return PasswordController{
+		passwordUsecase: usecase,
+	}
+}
+
+func (pc *PasswordController) ChangePassword(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response := model.Response{
+			Message: "Essa rota espera receber um id numérico",
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var change model.PasswordChange
+	if err := ctx.ShouldBindBodyWithJSON(&change); err != nil {
+		response := model.Response{
+			Message: "Informe new_password e, se a sessão não for recente, current_password",
+			Details: bindingDetails(ctx, err, &change),
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	claims, _ := middleware.Claims(ctx)
+	err = pc.passwordUsecase.ChangePassword(userID, claims.SessionID, change, actorFromRequest(ctx))
+
+	var validationErr usecase.ValidationError
+	if errors.As(err, &validationErr) {
+		response := model.Response{
+			Message: "A nova senha não atende à política de senhas",
+			Details: validationErr.Fields,
+		}
+		ctx.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	if err == usecase.ErrInvalidCredentials {
+		response := model.Response{
+			Message: "A senha atual está incorreta",
+		}
+		ctx.JSON(http.StatusForbidden, response)
+		return
+	}
+
+	if err == usecase.ErrReauthRequired {
+		response := model.Response{
+			Message: "Informe a senha atual ou faça login novamente para trocar a senha",
+		}
+		ctx.JSON(http.StatusForbidden, response)
+		return
+	}
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,80 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/usecase"
+)
+
+type PreferencesController struct {
+	preferencesUsecase usecase.PreferencesUsecase
+}
+
+func NewPreferencesController(usecase usecase.PreferencesUsecase) PreferencesController {
+	return PreferencesController{
+		preferencesUsecase: usecase,
+	}
+}
+
+func (pc *PreferencesController) GetPreferences(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response := model.Response{
+			Message: "Essa rota espera receber um id numérico",
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	prefs, err := pc.preferencesUsecase.GetPreferences(userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, prefs)
+}
+
+func (pc *PreferencesController) UpdatePreferences(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response := model.Response{
+			Message: "Essa rota espera receber um id numérico",
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var update model.PreferencesUpdate
+	if err := ctx.ShouldBindBodyWithJSON(&update); err != nil {
+		response := model.Response{
+			Message: "O corpo deve ser {\"login_alerts\": true|false, \"locale\": ...}",
+			Details: bindingDetails(ctx, err, &update),
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	updated, err := pc.preferencesUsecase.UpdatePreferences(userID, update)
+
+	var validationErr usecase.ValidationError
+	if errors.As(err, &validationErr) {
+		response := model.Response{
+			Message: "As preferências informadas são inválidas",
+			Details: validationErr.Fields,
+		}
+		ctx.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updated)
+}
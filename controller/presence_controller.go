@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/mask"
+	"github.com/pytsx/goapi/middleware"
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/usecase"
+)
+
+type PresenceController struct {
+	presenceUsecase usecase.PresenceUsecase
+}
+
+func NewPresenceController(usecase usecase.PresenceUsecase) PresenceController {
+	return PresenceController{
+		presenceUsecase: usecase,
+	}
+}
+
+// Heartbeat mantém o usuário autenticado online por mais um TTL
+func (pc *PresenceController) Heartbeat(ctx *gin.Context) {
+	claims, _ := middleware.Claims(ctx)
+	userID, err := strconv.Atoi(claims.Subject)
+	if err != nil {
+		response := model.Response{
+			Message: "Token sem um usuário válido",
+		}
+		ctx.JSON(http.StatusUnauthorized, response)
+		return
+	}
+
+	if err := pc.presenceUsecase.Heartbeat(userID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (pc *PresenceController) GetOnlineUsers(ctx *gin.Context) {
+	users, err := pc.presenceUsecase.OnlineUsers()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, mask.Apply(users, viewerFromRequest(ctx)))
+}
@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// abaixo disso o gzip custa mais CPU do que economiza em banda
+	compressMinBytes = 1024
+	// buffers maiores que isso (listagens enormes) não voltam ao pool para não reter memória
+	maxPooledBuffer = 1 << 20
+)
+
+var (
+	bufferPool = sync.Pool{
+		New: func() any { return new(bytes.Buffer) },
+	}
+	gzipPool = sync.Pool{
+		New: func() any {
+			writer, _ := gzip.NewWriterLevel(nil, gzip.BestSpeed)
+			return writer
+		},
+	}
+)
+
+// renderJSON substitui ctx.JSON nas listagens: serializa em um buffer reaproveitado
+// e, como o tamanho é conhecido antes de escrever, só comprime com gzip as respostas
+// grandes o bastante quando o cliente aceita
+func renderJSON(ctx *gin.Context, status int, v any) {
+	buffer := bufferPool.Get().(*bytes.Buffer)
+	buffer.Reset()
+	defer func() {
+		if buffer.Cap() <= maxPooledBuffer {
+			bufferPool.Put(buffer)
+		}
+	}()
+
+	if err := json.NewEncoder(buffer).Encode(v); err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	header := ctx.Writer.Header()
+	header.Set("Content-Type", "application/json; charset=utf-8")
+	header.Add("Vary", "Accept-Encoding")
+
+	if buffer.Len() < compressMinBytes || !acceptsGzip(ctx.GetHeader("Accept-Encoding")) {
+		header.Set("Content-Length", strconv.Itoa(buffer.Len()))
+		ctx.Status(status)
+		ctx.Writer.Write(buffer.Bytes())
+		return
+	}
+
+	header.Set("Content-Encoding", "gzip")
+	ctx.Status(status)
+
+	writer := gzipPool.Get().(*gzip.Writer)
+	defer gzipPool.Put(writer)
+	writer.Reset(ctx.Writer)
+	writer.Write(buffer.Bytes())
+	writer.Close()
+}
+
+// acceptsGzip interpreta o Accept-Encoding, respeitando gzip;q=0 como recusa
+func acceptsGzip(acceptEncoding string) bool {
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if !strings.EqualFold(strings.TrimSpace(coding), "gzip") && strings.TrimSpace(coding) != "*" {
+			continue
+		}
+
+		_, q, found := strings.Cut(strings.ReplaceAll(params, " ", ""), "q=")
+		if !found {
+			return true
+		}
+		weight, err := strconv.ParseFloat(q, 64)
+		return err == nil && weight > 0
+	}
+	return false
+}
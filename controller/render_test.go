@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/mask"
+	"github.com/pytsx/goapi/model"
+)
+
+// BenchmarkRenderUsers mede o caminho do GET /users: mascaramento e serialização de
+// uma página de usuários, com e sem gzip
+//
+//	go test ./controller -run '^$' -bench RenderUsers -benchmem
+func BenchmarkRenderUsers(b *testing.B) {
+	gin.SetMode(gin.TestMode)
+
+	users := make([]model.User, 100)
+	for i := range users {
+		users[i] = model.User{
+			ID:                  i + 1,
+			Name:                "Usuário " + strconv.Itoa(i+1),
+			Email:               "usuario" + strconv.Itoa(i+1) + "@example.com",
+			ImgURL:              model.NullString("https://cdn.example.com/avatars/" + strconv.Itoa(i+1) + ".png"),
+			CreatedAt:           model.Time{Time: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)},
+			ProfileCompleteness: 75,
+		}
+	}
+	viewer := mask.Viewer{UserID: 1}
+
+	for _, encoding := range []string{"identity", "gzip"} {
+		b.Run(encoding, func(b *testing.B) {
+			request := httptest.NewRequest(http.MethodGet, "/users", nil)
+			request.Header.Set("Accept-Encoding", encoding)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				ctx, _ := gin.CreateTestContext(httptest.NewRecorder())
+				ctx.Request = request
+				renderJSON(ctx, http.StatusOK, mask.Apply(users, viewer))
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/middleware"
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/usecase"
+)
+
+type SessionController struct {
+	sessionUsecase usecase.SessionUsecase
+}
+
+func NewSessionController(usecase usecase.SessionUsecase) SessionController {
+	return SessionController{
+		sessionUsecase: usecase,
+	}
+}
+
+func (sc *SessionController) GetSessions(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response := model.Response{
+			Message: "Essa rota espera receber um id numérico",
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	sessions, err := sc.sessionUsecase.GetSessions(userID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if claims, ok := middleware.Claims(ctx); ok {
+		for i := range sessions {
+			sessions[i].Current = sessions[i].ID == claims.SessionID
+		}
+	}
+
+	renderJSON(ctx, http.StatusOK, sessions)
+}
+
+// RevokeSession revoga a sessão :sid ou, sem ela na rota, todas as sessões do usuário
+func (sc *SessionController) RevokeSession(ctx *gin.Context) {
+	userID, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response := model.Response{
+			Message: "Essa rota espera receber um id numérico",
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	var ids []string
+	if sid := ctx.Param("sid"); sid != "" {
+		ids = append(ids, sid)
+	}
+
+	revoked, err := sc.sessionUsecase.RevokeSessions(userID, ids...)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if len(ids) > 0 && len(revoked) == 0 {
+		response := model.Response{
+			Message: "Nenhuma sessão ativa foi localizada com o id fornecido",
+		}
+		ctx.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,155 @@
+package controller
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+var errSNSSignature = errors.New("assinatura SNS inválida")
+
+// certificados de assinatura do SNS: https://sns.<região>.amazonaws.com/SimpleNotificationService-<id>.pem
+var snsCertHost = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+// snsMessage é o envelope das mensagens HTTP do SNS
+type snsMessage struct {
+	Type             string `json:"Type"`
+	MessageID        string `json:"MessageId"`
+	Token            string `json:"Token"`
+	TopicArn         string `json:"TopicArn"`
+	Subject          string `json:"Subject"`
+	Message          string `json:"Message"`
+	SubscribeURL     string `json:"SubscribeURL"`
+	Timestamp        string `json:"Timestamp"`
+	SignatureVersion string `json:"SignatureVersion"`
+	Signature        string `json:"Signature"`
+	SigningCertURL   string `json:"SigningCertURL"`
+}
+
+// stringToSign monta o texto assinado conforme o tipo da mensagem, na ordem documentada pela AWS
+func (m snsMessage) stringToSign() string {
+	fields := [][2]string{{"Message", m.Message}, {"MessageId", m.MessageID}}
+	switch m.Type {
+	case "Notification":
+		if m.Subject != "" {
+			fields = append(fields, [2]string{"Subject", m.Subject})
+		}
+		fields = append(fields, [2]string{"Timestamp", m.Timestamp}, [2]string{"TopicArn", m.TopicArn})
+	default:
+		// SubscriptionConfirmation e UnsubscribeConfirmation
+		fields = append(fields,
+			[2]string{"SubscribeURL", m.SubscribeURL},
+			[2]string{"Timestamp", m.Timestamp},
+			[2]string{"Token", m.Token},
+			[2]string{"TopicArn", m.TopicArn},
+		)
+	}
+	fields = append(fields, [2]string{"Type", m.Type})
+
+	var b strings.Builder
+	for _, field := range fields {
+		b.WriteString(field[0] + "\n" + field[1] + "\n")
+	}
+	return b.String()
+}
+
+// snsCerts guarda os certificados já baixados; a AWS troca o certificado raramente
+type snsCerts struct {
+	mu    sync.Mutex
+	byURL map[string]*rsa.PublicKey
+}
+
+// verifySNSMessage confere a assinatura com o certificado indicado em SigningCertURL,
+// aceito apenas de um host sns.*.amazonaws.com
+func (ec *EmailWebhookController) verifySNSMessage(ctx context.Context, m snsMessage) error {
+	var hash crypto.Hash
+	var digest []byte
+	switch m.SignatureVersion {
+	case "1":
+		sum := sha1.Sum([]byte(m.stringToSign()))
+		hash, digest = crypto.SHA1, sum[:]
+	case "2":
+		sum := sha256.Sum256([]byte(m.stringToSign()))
+		hash, digest = crypto.SHA256, sum[:]
+	default:
+		return fmt.Errorf("%w: SignatureVersion %q", errSNSSignature, m.SignatureVersion)
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(m.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: %v", errSNSSignature, err)
+	}
+
+	key, err := ec.snsSigningKey(ctx, m.SigningCertURL)
+	if err != nil {
+		return err
+	}
+
+	if err := rsa.VerifyPKCS1v15(key, hash, digest, signature); err != nil {
+		return errSNSSignature
+	}
+	return nil
+}
+
+func (ec *EmailWebhookController) snsSigningKey(ctx context.Context, certURL string) (*rsa.PublicKey, error) {
+	parsed, err := url.Parse(certURL)
+	if err != nil || parsed.Scheme != "https" || !snsCertHost.MatchString(parsed.Hostname()) ||
+		!strings.HasSuffix(parsed.Path, ".pem") {
+		return nil, fmt.Errorf("%w: SigningCertURL não é do SNS: %q", errSNSSignature, certURL)
+	}
+
+	ec.certs.mu.Lock()
+	key, ok := ec.certs.byURL[certURL]
+	ec.certs.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, parsed.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := ec.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("baixando o certificado SNS: %s", resp.Status)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(content)
+	if block == nil {
+		return nil, fmt.Errorf("%w: certificado sem bloco PEM", errSNSSignature)
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	key, ok = cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: certificado sem chave RSA", errSNSSignature)
+	}
+
+	ec.certs.mu.Lock()
+	ec.certs.byURL[certURL] = key
+	ec.certs.mu.Unlock()
+	return key, nil
+}
@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/usecase"
+)
+
+type TaskController struct {
+	taskUsecase     usecase.TaskUsecase
+	userTaskUsecase usecase.UserTaskUsecase
+}
+
+func NewTaskController(taskUsecase usecase.TaskUsecase, userTaskUsecase usecase.UserTaskUsecase) TaskController {
+	return TaskController{
+		taskUsecase:     taskUsecase,
+		userTaskUsecase: userTaskUsecase,
+	}
+}
+
+// accepted responde 202 com a task criada e o endereço para acompanhá-la
+func accepted(ctx *gin.Context, task model.Task) {
+	ctx.Header("Location", "/tasks/"+strconv.FormatInt(task.ID, 10))
+	ctx.JSON(http.StatusAccepted, task)
+}
+
+func (tc *TaskController) GetTask(ctx *gin.Context) {
+	id, err := strconv.ParseInt(ctx.Param("id"), 10, 64)
+	if err != nil {
+		response := model.Response{
+			Message: "Essa rota espera receber um id numérico",
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	task, err := tc.taskUsecase.GetTask(id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if task == nil {
+		response := model.Response{
+			Message: "Nenhuma task foi localizada com o id fornecido",
+		}
+		ctx.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, task)
+}
+
+func (tc *TaskController) ExportUsers(ctx *gin.Context) {
+	task, err := tc.userTaskUsecase.RequestExport(actorFromRequest(ctx))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	accepted(ctx, task)
+}
+
+// ImportUsers recebe uma lista de usuários no mesmo formato do cadastro
+func (tc *TaskController) ImportUsers(ctx *gin.Context) {
+	var users []model.User
+	if err := ctx.ShouldBindBodyWithJSON(&users); err != nil {
+		response := model.Response{
+			Message: "O corpo deve ser uma lista de usuários",
+			Details: bindingDetails(ctx, err, &users),
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	task, err := tc.userTaskUsecase.RequestImport(users, actorFromRequest(ctx))
+
+	var validationErr usecase.ValidationError
+	if errors.As(err, &validationErr) {
+		response := model.Response{
+			Message: "A importação é inválida",
+			Details: validationErr.Fields,
+		}
+		ctx.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	accepted(ctx, task)
+}
+
+func (tc *TaskController) AnonymizeUser(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response := model.Response{
+			Message: "Essa rota espera receber um id numérico",
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	task, err := tc.userTaskUsecase.RequestAnonymize(id, actorFromRequest(ctx))
+	if err == usecase.ErrUserNotFound {
+		response := model.Response{
+			Message: "Nenhum usuário foi localizado com o id fornecido",
+		}
+		ctx.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	accepted(ctx, task)
+}
@@ -0,0 +1,40 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/middleware"
+	"github.com/pytsx/goapi/usecase"
+)
+
+type UsageController struct {
+	usageUsecase usecase.UsageUsecase
+}
+
+func NewUsageController(usecase usecase.UsageUsecase) UsageController {
+	return UsageController{
+		usageUsecase: usecase,
+	}
+}
+
+func (uc *UsageController) GetReport(ctx *gin.Context) {
+	report, err := uc.usageUsecase.Report()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+// GetKeyUsage informa o consumo do dia da chave usada na própria requisição
+func (uc *UsageController) GetKeyUsage(ctx *gin.Context) {
+	usage, err := uc.usageUsecase.KeyUsage(ctx.GetHeader(middleware.APIKeyHeader))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, usage)
+}
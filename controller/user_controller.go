@@ -1,48 +1,265 @@
 package controller
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/mask"
+	"github.com/pytsx/goapi/middleware"
 	"github.com/pytsx/goapi/model"
 	"github.com/pytsx/goapi/usecase"
 )
 
+const (
+	defaultPageSize = 50
+	maxPageSize     = 500
+	// quantidade de linhas escritas entre cada flush no modo de streaming
+	streamFlushEvery = 500
+)
+
 type UserController struct {
-	userUsecase usecase.UserUsecase
+	userUsecase   usecase.UserUsecase
+	streamMaxRows int
 }
 
-func NewUserController(usecase usecase.UserUsecase) UserController {
+func NewUserController(usecase usecase.UserUsecase, streamMaxRows int) UserController {
 	return UserController{
-		userUsecase: usecase,
+		userUsecase:   usecase,
+		streamMaxRows: streamMaxRows,
 	}
 }
 
+// GetUsers é paginado por ?limit= e ?offset= e aceita os filtros ?name=,
+// ?created_after= e ?created_before= (RFC 3339) e a ordenação ?sort= (ex.: -created_at).
+// Admins podem pedir ?paginate=false para receber todos os usuários em streaming.
 func (uc *UserController) GetUsers(ctx *gin.Context) {
-	products, err := uc.userUsecase.GetUsers()
+	if ctx.Query("paginate") == "false" {
+		if !middleware.IsAdmin(ctx) {
+			response := model.Response{
+				Message: "Apenas administradores podem desativar a paginação",
+			}
+			ctx.JSON(http.StatusForbidden, response)
+			return
+		}
+
+		uc.streamUsers(ctx)
+		return
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", strconv.Itoa(defaultPageSize)))
+	if err != nil || limit < 1 || limit > maxPageSize {
+		response := model.Response{
+			Message: "O parâmetro limit deve estar entre 1 e " + strconv.Itoa(maxPageSize),
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	offset, err := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
+	if err != nil || offset < 0 {
+		response := model.Response{
+			Message: "O parâmetro offset deve ser um número não negativo",
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	filter := model.UserFilter{
+		Name: ctx.Query("name"),
+		Sort: ctx.Query("sort"),
+	}
+	if filter.CreatedAfter, err = timeQuery(ctx, "created_after"); err != nil {
+		return
+	}
+	if filter.CreatedBefore, err = timeQuery(ctx, "created_before"); err != nil {
+		return
+	}
+	if filter.MinCompleteness, err = intQuery(ctx, "completeness_min"); err != nil {
+		return
+	}
+	if filter.MaxCompleteness, err = intQuery(ctx, "completeness_max"); err != nil {
+		return
+	}
+
+	users := uc.userUsecase.WithContext(middleware.DBContext(ctx))
+	products, err := users.GetUsers(filter, limit, offset)
+
+	var validationErr usecase.ValidationError
+	if errors.As(err, &validationErr) {
+		response := model.Response{
+			Message: "Os filtros da listagem são inválidos",
+			Details: validationErr.Fields,
+		}
+		ctx.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
 
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, products)
+	renderJSON(ctx, http.StatusOK, mask.Apply(products, viewerFromRequest(ctx)))
+}
+
+// timeQuery lê um parâmetro RFC 3339 opcional; se for inválido, responde 400 e devolve o erro
+func timeQuery(ctx *gin.Context, param string) (*time.Time, error) {
+	value := ctx.Query(param)
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		response := model.Response{
+			Message: "O parâmetro " + param + " deve ser uma data RFC 3339, ex.: 2026-01-31T00:00:00Z",
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return nil, err
+	}
+	return &parsed, nil
+}
+
+func intQuery(ctx *gin.Context, param string) (*int, error) {
+	value := ctx.Query(param)
+	if value == "" {
+		return nil, nil
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		response := model.Response{
+			Message: "O parâmetro " + param + " deve ser um número inteiro",
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return nil, err
+	}
+	return &parsed, nil
 }
 
+// streamUsers escreve o array JSON incrementalmente, com flush periódico. Se o
+// limite de linhas for atingido, o trailer X-Truncated informa o corte.
+// Não usa o prazo de DBContext: a exportação é limitada pelo statement_timeout do pool.
+func (uc *UserController) streamUsers(ctx *gin.Context) {
+	ctx.Header("Content-Type", "application/json; charset=utf-8")
+	ctx.Header("Trailer", "X-Truncated")
+	ctx.Header("X-Row-Cap", strconv.Itoa(uc.streamMaxRows))
+	ctx.Status(http.StatusOK)
+
+	encoder := json.NewEncoder(ctx.Writer)
+	viewer := viewerFromRequest(ctx)
+	count := 0
+
+	ctx.Writer.WriteString("[")
+	truncated, err := uc.userUsecase.StreamUsers(uc.streamMaxRows, func(user model.User) error {
+		if count > 0 {
+			if _, err := ctx.Writer.WriteString(","); err != nil {
+				return err
+			}
+		}
+		if err := encoder.Encode(mask.Apply(user, viewer)); err != nil {
+			return err
+		}
+
+		count++
+		if count%streamFlushEvery == 0 {
+			ctx.Writer.Flush()
+		}
+		return nil
+	})
+
+	if err != nil {
+		// o status já foi enviado: interrompe a resposta para o cliente não receber um JSON truncado como válido
+		log.Printf("streaming de usuários interrompido após %d linhas: %v", count, err)
+		panic(http.ErrAbortHandler)
+	}
+
+	ctx.Writer.WriteString("]")
+	ctx.Writer.Header().Set("X-Truncated", strconv.FormatBool(truncated))
+}
+
+// CreateUser é o cadastro feito por admins, que dispensa convite
 func (uc *UserController) CreateUser(ctx *gin.Context) {
+	uc.createUser(ctx, false)
+}
 
+// Register é o cadastro público. O convite vem em invite_code no corpo: a página dos links
+// gerados em /admin/invitations lê o ?invite= e o envia, sem expor o código nos logs da API
+func (uc *UserController) Register(ctx *gin.Context) {
+	uc.createUser(ctx, true)
+}
+
+func (uc *UserController) createUser(ctx *gin.Context, public bool) {
 	var user model.User
 	// popula o objeto ´user´ com os valores passados na requisição. Caso não corresponda com um user, retorna um erro para o requisitante
-	err := ctx.BindJSON(&user)
-	if err != nil {
-		// informa que o erro foi da aplicação requisitante
-		ctx.JSON(http.StatusBadRequest, err)
+	if err := ctx.ShouldBindBodyWithJSON(&user); err != nil {
+		// informa que o erro foi da aplicação requisitante, apontando o campo quando possível
+		response := model.Response{
+			Message: "O corpo deve ser um usuário em JSON",
+			Details: bindingDetails(ctx, err, &user),
+		}
+		ctx.JSON(http.StatusBadRequest, response)
 		return
 	}
 
 	// chama o usecase para criar o usuário
-	insertedUser, err := uc.userUsecase.CreateUser(user)
+	user.Locale = localeFromRequest(ctx)
+
+	users := uc.userUsecase.WithContext(middleware.DBContext(ctx))
+	create := users.CreateUser
+	if public {
+		create = users.Register
+	}
+	insertedUser, err := create(user, actorFromRequest(ctx))
+
+	var validationErr usecase.ValidationError
+	if errors.As(err, &validationErr) {
+		response := model.Response{
+			Message: "Os dados do usuário são inválidos",
+			Details: validationErr.Fields,
+		}
+		ctx.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	if err == usecase.ErrEmailTaken {
+		response := model.Response{
+			Message: "Já existe um usuário com este email",
+		}
+		ctx.JSON(http.StatusConflict, response)
+		return
+	}
+
+	if err == usecase.ErrUserQuotaExceeded {
+		response := model.Response{
+			Message: "O limite de usuários do plano foi atingido",
+		}
+		ctx.JSON(http.StatusPaymentRequired, response)
+		return
+	}
+
+	if err == usecase.ErrContentRejected {
+		response := model.Response{
+			Message: "O nome informado não é permitido",
+		}
+		ctx.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	if err == usecase.ErrInvitationRequired {
+		response := model.Response{
+			Message: "O cadastro está aberto apenas para convidados: informe um convite válido",
+		}
+		ctx.JSON(http.StatusForbidden, response)
+		return
+	}
 
 	if err != nil {
 		// aconteceu um erro no ´userRepository´, portanto foi interno da aplicação
@@ -50,6 +267,7 @@ func (uc *UserController) CreateUser(ctx *gin.Context) {
 		return
 	}
 
+	// quem cadastra acabou de informar os dados, então a resposta não é mascarada
 	ctx.JSON(http.StatusCreated, insertedUser)
 }
 
@@ -72,7 +290,8 @@ func (uc *UserController) GetUser(ctx *gin.Context) {
 		return
 	}
 
-	user, err := uc.userUsecase.GetUser(safeId)
+	users := uc.userUsecase.WithContext(middleware.DBContext(ctx))
+	user, err := users.GetUser(safeId)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, err)
 		return
@@ -86,5 +305,186 @@ func (uc *UserController) GetUser(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, user)
+	ctx.JSON(http.StatusOK, mask.Apply(user, viewerFromRequest(ctx)))
+}
+
+// GetChanges atende a sincronização incremental: ?since= recebe um timestamp RFC 3339
+// na primeira chamada e, nas seguintes, o next_cursor da resposta anterior
+func (uc *UserController) GetChanges(ctx *gin.Context) {
+	after, err := decodeChangeCursor(ctx.Query("since"))
+	if err != nil {
+		response := model.Response{
+			Message: "O parâmetro since deve ser um timestamp RFC 3339 ou o next_cursor de uma resposta anterior",
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	limit, err := strconv.Atoi(ctx.DefaultQuery("limit", strconv.Itoa(maxPageSize)))
+	if err != nil || limit < 1 || limit > maxPageSize {
+		response := model.Response{
+			Message: "O parâmetro limit deve estar entre 1 e " + strconv.Itoa(maxPageSize),
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	users := uc.userUsecase.WithContext(middleware.DBContext(ctx))
+	changes, next, hasMore, err := users.GetChanges(after, limit)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	renderJSON(ctx, http.StatusOK, model.UserChanges{
+		Changes:    changes,
+		NextCursor: encodeChangeCursor(next),
+		HasMore:    hasMore,
+	})
+}
+
+// GetRelated é restrito ao próprio usuário e a administradores
+func (uc *UserController) GetRelated(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response := model.Response{
+			Message: "Essa rota espera receber um id numérico",
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	viewer := viewerFromRequest(ctx)
+	if !viewer.Privileged && viewer.UserID != id {
+		response := model.Response{
+			Message: "Apenas o próprio usuário ou administradores podem ver esses dados",
+		}
+		ctx.JSON(http.StatusForbidden, response)
+		return
+	}
+
+	users := uc.userUsecase.WithContext(middleware.DBContext(ctx))
+	related, err := users.GetRelated(id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if related == nil {
+		response := model.Response{
+			Message: "Nenhum usuário foi localizado com o id fornecido",
+		}
+		ctx.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, mask.Apply(related, viewer))
+}
+
+func (uc *UserController) DeleteUser(ctx *gin.Context) {
+	id, err := strconv.Atoi(ctx.Param("id"))
+	if err != nil {
+		response := model.Response{
+			Message: "Essa rota espera receber um id numérico",
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	users := uc.userUsecase.WithContext(middleware.DBContext(ctx))
+	err = users.DeleteUser(id, actorFromRequest(ctx))
+	if err == usecase.ErrUserNotFound {
+		response := model.Response{
+			Message: "Nenhum usuário foi localizado com o id fornecido",
+		}
+		ctx.JSON(http.StatusNotFound, response)
+		return
+	}
+
+	var dependentsErr usecase.DependentsError
+	if errors.As(err, &dependentsErr) {
+		details := map[string]string{}
+		for relation, count := range dependentsErr.Relations {
+			details[relation] = "possui registros vinculados ao usuário"
+			if count > 0 {
+				details[relation] = "possui " + strconv.FormatInt(count, 10) + " registros vinculados ao usuário"
+			}
+		}
+
+		response := model.Response{
+			Message: "O usuário não pode ser removido enquanto houver registros dependentes",
+			Details: details,
+		}
+		ctx.JSON(http.StatusConflict, response)
+		return
+	}
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+func (uc *UserController) CheckDuplicates(ctx *gin.Context) {
+	var check model.DuplicateCheck
+	if err := ctx.ShouldBindBodyWithJSON(&check); err != nil {
+		response := model.Response{
+			Message: "O corpo deve ser {\"name\": ..., \"email\": ...}",
+			Details: bindingDetails(ctx, err, &check),
+		}
+		ctx.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	users := uc.userUsecase.WithContext(middleware.DBContext(ctx))
+	matches, err := users.CheckDuplicates(check)
+
+	var validationErr usecase.ValidationError
+	if errors.As(err, &validationErr) {
+		response := model.Response{
+			Message: "Os dados informados são inválidos",
+			Details: validationErr.Fields,
+		}
+		ctx.JSON(http.StatusUnprocessableEntity, response)
+		return
+	}
+
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, mask.Apply(matches, viewerFromRequest(ctx)))
+}
+
+// o cursor é opaco para o cliente: instante da última alteração entregue e id do usuário
+func encodeChangeCursor(cursor model.ChangeCursor) string {
+	raw := cursor.At.UTC().Format(time.RFC3339Nano) + "|" + strconv.Itoa(cursor.UserID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeChangeCursor(since string) (model.ChangeCursor, error) {
+	if at, err := time.Parse(time.RFC3339, since); err == nil {
+		return model.ChangeCursor{At: at}, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(since)
+	if err != nil {
+		return model.ChangeCursor{}, err
+	}
+	at, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return model.ChangeCursor{}, errors.New("cursor malformado")
+	}
+
+	var cursor model.ChangeCursor
+	if cursor.At, err = time.Parse(time.RFC3339Nano, at); err != nil {
+		return model.ChangeCursor{}, err
+	}
+	if cursor.UserID, err = strconv.Atoi(id); err != nil {
+		return model.ChangeCursor{}, err
+	}
+	return cursor, nil
 }
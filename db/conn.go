@@ -2,9 +2,11 @@ package db
 
 import (
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
+	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
 const (
@@ -15,21 +17,33 @@ const (
 	dbname   = "postgres"
 )
 
-func ConnectDB() (*sql.DB, error) {
+// Address identifica o servidor nos relatórios de diagnóstico
+func Address() string {
+	return fmt.Sprintf("%s:%d/%s", host, port, dbname)
+}
+
+// ConnectDB prepara o pool de conexões. A conexão em si é validada pela
+// verificação de dependências no boot, que tenta novamente antes de desistir.
+// statementTimeout é aplicado pelo Postgres a cada comando das conexões do pool (zero desativa).
+// Os decorators envolvem o conector do driver (ex.: injeção de falhas do modo de caos).
+func ConnectDB(statementTimeout time.Duration, decorators ...func(driver.Connector) driver.Connector) (*sql.DB, error) {
 	psqlInfo := fmt.Sprintf("host=%s port=%d user=%s "+
 		"password=%s dbname=%s sslmode=disable",
 		host, port, user, password, dbname)
+	if statementTimeout > 0 {
+		// parâmetros desconhecidos pelo driver são enviados ao servidor na abertura da conexão
+		psqlInfo += fmt.Sprintf(" statement_timeout=%d", statementTimeout.Milliseconds())
+	}
 
-	db, err := sql.Open("postgres", psqlInfo)
-
+	connector, err := pq.NewConnector(psqlInfo)
 	if err != nil {
 		return nil, err
 	}
 
-	err = db.Ping()
-	if err != nil {
-		return nil, err
+	var wrapped driver.Connector = connector
+	for _, decorate := range decorators {
+		wrapped = decorate(wrapped)
 	}
 
-	return db, nil
+	return sql.OpenDB(wrapped), nil
 }
@@ -0,0 +1,184 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// colunas das quais os repositórios dependem, por tabela. Ao criar uma migração
+// que acrescente algo usado nas consultas, atualize aqui.
+var expectedColumns = map[string][]string{
+	"users": {"id", "name", "email", "img_url", "password_hash", "email_undeliverable", "created_at",
+		"moderation_status", "moderation_reason", "moderated_at",
+		"avatar_status", "avatar_reason", "avatar_quarantine_key", "email_canonical", "updated_at",
+		"profile_completeness"},
+	"user_preferences":   {"user_id", "login_alerts", "locale"},
+	"login_history":      {"id", "user_id", "device", "ip", "country", "city", "location", "created_at"},
+	"sessions":           {"id", "user_id", "refresh_token_hash", "device", "ip", "created_at", "last_seen_at", "expires_at", "revoked_at", "previous_refresh_token_hash"},
+	"consents":           {"id", "user_id", "policy", "version", "ip", "accepted_at"},
+	"email_suppressions": {"email", "reason", "provider", "detail", "created_at"},
+	"audit_log":          {"id", "actor", "action", "entity", "entity_id", "ip", "country", "metadata", "created_at"},
+	"jobs": {"id", "queue", "kind", "payload", "status", "attempts", "max_attempts", "last_error",
+		"run_at", "created_at", "updated_at"},
+	"api_key_usage":   {"key_id", "day", "requests"},
+	"runtime_config":  {"revision", "schema_version", "document", "actor", "created_at"},
+	"user_tombstones": {"user_id", "deleted_at"},
+	"tasks": {"id", "kind", "status", "progress", "result_url", "errors", "actor",
+		"created_at", "updated_at", "finished_at"},
+	"invitations": {"id", "code_hash", "code_hint", "note", "max_uses", "uses", "expires_at", "revoked_at",
+		"created_by", "created_at"},
+	"invitation_uses": {"id", "invitation_id", "user_id", "used_at"},
+}
+
+// índices dos quais dependem o desempenho das consultas (fila de jobs, busca por nome, etc.)
+var expectedIndexes = []string{
+	"login_history_user_id_idx",
+	"sessions_user_id_idx",
+	"users_moderation_status_idx",
+	"audit_log_entity_idx",
+	"audit_log_actor_idx",
+	"jobs_queue_pending_idx",
+	"jobs_running_idx",
+	"users_email_canonical_idx",
+	"users_name_trgm_idx",
+	"users_updated_at_idx",
+	"user_tombstones_deleted_at_idx",
+	"invitation_uses_invitation_idx",
+	"users_profile_completeness_idx",
+	"users_email_lower_idx",
+}
+
+// SchemaDrift compara o schema do banco com o que este binário espera
+type SchemaDrift struct {
+	// maior versão embutida no binário e maior versão aplicada no banco
+	ExpectedVersion int
+	AppliedVersion  int
+	// versões aplicadas que o binário desconhece: o banco foi migrado por uma versão
+	// mais nova. Esperado durante um deploy gradual, por isso não é tratado como drift.
+	UnknownVersions []int
+	// "tabela.coluna" ausentes
+	MissingColumns []string
+	MissingIndexes []string
+}
+
+// Drifted indica que falta algo de que os repositórios dependem
+func (d SchemaDrift) Drifted() bool {
+	return len(d.MissingColumns) > 0 || len(d.MissingIndexes) > 0
+}
+
+func (d SchemaDrift) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "schema: versão esperada %d, aplicada %d\n", d.ExpectedVersion, d.AppliedVersion)
+
+	if len(d.UnknownVersions) > 0 {
+		fmt.Fprintf(&b, "  [aviso] migrações aplicadas desconhecidas por este binário: %v\n", d.UnknownVersions)
+	}
+	for _, column := range d.MissingColumns {
+		fmt.Fprintf(&b, "  - coluna ausente: %s\n", column)
+	}
+	for _, index := range d.MissingIndexes {
+		fmt.Fprintf(&b, "  - índice ausente: %s\n", index)
+	}
+	if !d.Drifted() {
+		b.WriteString("  colunas e índices conferem\n")
+	}
+
+	return b.String()
+}
+
+// CheckSchema lê o catálogo do banco e aponta as diferenças em relação ao esperado
+func CheckSchema(ctx context.Context, conn *sql.DB) (SchemaDrift, error) {
+	var drift SchemaDrift
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		return drift, err
+	}
+	known := map[int]bool{}
+	for _, m := range migrations {
+		known[m.version] = true
+		drift.ExpectedVersion = max(drift.ExpectedVersion, m.version)
+	}
+
+	rows, err := conn.QueryContext(ctx, "SELECT version FROM schema_migrations ORDER BY version")
+	if err != nil {
+		return drift, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return drift, err
+		}
+		drift.AppliedVersion = max(drift.AppliedVersion, version)
+		if !known[version] {
+			drift.UnknownVersions = append(drift.UnknownVersions, version)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return drift, err
+	}
+
+	tables := make([]string, 0, len(expectedColumns))
+	for table := range expectedColumns {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	columns := map[string]bool{}
+	rows, err = conn.QueryContext(ctx, "SELECT table_name, column_name FROM information_schema.columns"+
+		" WHERE table_schema = current_schema() AND table_name = ANY($1)", pq.Array(tables))
+	if err != nil {
+		return drift, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var table, column string
+		if err := rows.Scan(&table, &column); err != nil {
+			return drift, err
+		}
+		columns[table+"."+column] = true
+	}
+	if err := rows.Err(); err != nil {
+		return drift, err
+	}
+
+	for _, table := range tables {
+		for _, column := range expectedColumns[table] {
+			if !columns[table+"."+column] {
+				drift.MissingColumns = append(drift.MissingColumns, table+"."+column)
+			}
+		}
+	}
+
+	indexes := map[string]bool{}
+	rows, err = conn.QueryContext(ctx, "SELECT indexname FROM pg_indexes"+
+		" WHERE schemaname = current_schema() AND indexname = ANY($1)", pq.Array(expectedIndexes))
+	if err != nil {
+		return drift, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var index string
+		if err := rows.Scan(&index); err != nil {
+			return drift, err
+		}
+		indexes[index] = true
+	}
+	if err := rows.Err(); err != nil {
+		return drift, err
+	}
+
+	for _, index := range expectedIndexes {
+		if !indexes[index] {
+			drift.MissingIndexes = append(drift.MissingIndexes, index)
+		}
+	}
+
+	return drift, nil
+}
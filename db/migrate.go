@@ -0,0 +1,146 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+type migration struct {
+	version int
+	name    string
+	sql     string
+}
+
+// Migrate aplica, em ordem, as migrações ainda não registradas em schema_migrations
+func Migrate(conn *sql.DB) error {
+	_, err := conn.Exec("CREATE TABLE IF NOT EXISTS schema_migrations (" +
+		"version INTEGER PRIMARY KEY, " +
+		"applied_at TIMESTAMPTZ NOT NULL DEFAULT now())")
+	if err != nil {
+		return err
+	}
+
+	migrations, err := loadMigrations()
+	if err != nil {
+		return err
+	}
+
+	for _, m := range migrations {
+		var applied bool
+		err := conn.QueryRow("SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)", m.version).
+			Scan(&applied)
+		if err != nil {
+			return err
+		}
+		if applied {
+			continue
+		}
+
+		if err := apply(conn, m); err != nil {
+			return fmt.Errorf("migração %s: %w", m.name, err)
+		}
+	}
+
+	return nil
+}
+
+func apply(conn *sql.DB, m migration) error {
+	tx, err := conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// o statement_timeout do pool (DB_STATEMENT_TIMEOUT) interromperia os backfills de
+	// tabelas grandes no meio; SET LOCAL vale só para esta transação
+	if _, err := tx.Exec("SET LOCAL statement_timeout = 0"); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(m.sql); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("INSERT INTO schema_migrations (version) VALUES ($1)", m.version); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// os arquivos seguem o padrão <versão>_<descrição>.sql
+func loadMigrations() ([]migration, error) {
+	files, err := migrationFiles.ReadDir("migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []migration
+	for _, file := range files {
+		prefix, _, _ := strings.Cut(file.Name(), "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return nil, fmt.Errorf("nome de migração inválido: %s", file.Name())
+		}
+
+		content, err := migrationFiles.ReadFile(path.Join("migrations", file.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migrations = append(migrations, migration{
+			version: version,
+			name:    file.Name(),
+			sql:     string(content),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].version < migrations[j].version
+	})
+
+	return migrations, nil
+}
+
+// PendingMigrations lista as migrações embutidas no binário que ainda não foram aplicadas
+func PendingMigrations(ctx context.Context, conn *sql.DB) ([]string, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := conn.QueryContext(ctx, "SELECT version FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int]bool{}
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return nil, err
+		}
+		applied[version] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var pending []string
+	for _, m := range migrations {
+		if !applied[m.version] {
+			pending = append(pending, m.name)
+		}
+	}
+	return pending, nil
+}
@@ -0,0 +1,19 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_hash TEXT NOT NULL DEFAULT '';
+
+CREATE TABLE user_preferences (
+    user_id INTEGER PRIMARY KEY REFERENCES users (id) ON DELETE CASCADE,
+    login_alerts BOOLEAN NOT NULL DEFAULT TRUE
+);
+
+CREATE TABLE login_history (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    device TEXT NOT NULL,
+    ip VARCHAR(45) NOT NULL,
+    country VARCHAR(2) NOT NULL DEFAULT '',
+    city VARCHAR(255) NOT NULL DEFAULT '',
+    location TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX login_history_user_id_idx ON login_history (user_id);
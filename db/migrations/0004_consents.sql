@@ -0,0 +1,9 @@
+CREATE TABLE consents (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+    policy VARCHAR(64) NOT NULL,
+    version VARCHAR(64) NOT NULL,
+    ip VARCHAR(45) NOT NULL,
+    accepted_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    UNIQUE (user_id, policy, version)
+);
@@ -0,0 +1,9 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_undeliverable BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE email_suppressions (
+    email VARCHAR(255) PRIMARY KEY,
+    reason VARCHAR(16) NOT NULL,
+    provider VARCHAR(16) NOT NULL,
+    detail TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS moderation_status VARCHAR(16) NOT NULL DEFAULT 'approved';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS moderation_reason TEXT NOT NULL DEFAULT '';
+ALTER TABLE users ADD COLUMN IF NOT EXISTS moderated_at TIMESTAMPTZ;
+
+CREATE INDEX users_moderation_status_idx ON users (moderation_status) WHERE moderation_status = 'flagged';
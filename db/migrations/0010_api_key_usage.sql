@@ -0,0 +1,6 @@
+CREATE TABLE api_key_usage (
+    key_id VARCHAR(16) NOT NULL,
+    day DATE NOT NULL,
+    requests BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (key_id, day)
+);
@@ -0,0 +1,14 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- mesma regra de normalize.CanonicalEmail, aplicada aos registros existentes
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_canonical VARCHAR(255) NOT NULL DEFAULT '';
+
+UPDATE users SET email_canonical =
+    CASE WHEN lower(split_part(email, '@', 2)) IN ('gmail.com', 'googlemail.com')
+        THEN replace(split_part(split_part(lower(email), '@', 1), '+', 1), '.', '') || '@gmail.com'
+        ELSE split_part(split_part(lower(email), '@', 1), '+', 1) || '@' || lower(split_part(email, '@', 2))
+    END
+WHERE email_canonical = '';
+
+CREATE INDEX IF NOT EXISTS users_email_canonical_idx ON users (email_canonical);
+CREATE INDEX IF NOT EXISTS users_name_trgm_idx ON users USING gin (name gin_trgm_ops);
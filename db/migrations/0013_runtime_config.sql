@@ -0,0 +1,7 @@
+CREATE TABLE runtime_config (
+    revision BIGSERIAL PRIMARY KEY,
+    schema_version INTEGER NOT NULL,
+    document JSONB NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
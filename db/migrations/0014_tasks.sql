@@ -0,0 +1,12 @@
+CREATE TABLE tasks (
+    id BIGSERIAL PRIMARY KEY,
+    kind VARCHAR(64) NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    progress SMALLINT NOT NULL DEFAULT 0,
+    result_url TEXT NOT NULL DEFAULT '',
+    errors JSONB NOT NULL DEFAULT '[]',
+    actor VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    finished_at TIMESTAMPTZ
+);
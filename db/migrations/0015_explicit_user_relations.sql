@@ -0,0 +1,15 @@
+-- a remoção de usuários passa a tratar cada relação explicitamente no código
+-- (cascata, bloqueio ou órfão), em vez de depender do ON DELETE CASCADE
+ALTER TABLE user_preferences DROP CONSTRAINT user_preferences_user_id_fkey,
+    ADD CONSTRAINT user_preferences_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id);
+
+ALTER TABLE login_history DROP CONSTRAINT login_history_user_id_fkey,
+    ADD CONSTRAINT login_history_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id);
+
+ALTER TABLE sessions DROP CONSTRAINT sessions_user_id_fkey,
+    ADD CONSTRAINT sessions_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id);
+
+-- consentimentos sobrevivem à remoção como prova de aceite, sem identificar o titular
+ALTER TABLE consents ALTER COLUMN user_id DROP NOT NULL,
+    DROP CONSTRAINT consents_user_id_fkey,
+    ADD CONSTRAINT consents_user_id_fkey FOREIGN KEY (user_id) REFERENCES users (id);
@@ -0,0 +1,2 @@
+-- atividade recente em GET /users/:id/related busca pelo autor das ações
+CREATE INDEX IF NOT EXISTS audit_log_actor_idx ON audit_log (actor, created_at);
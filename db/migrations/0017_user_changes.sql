@@ -0,0 +1,22 @@
+-- sincronização incremental: updated_at mantido por trigger e lápides das remoções
+ALTER TABLE users ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+UPDATE users SET updated_at = created_at;
+
+CREATE OR REPLACE FUNCTION touch_updated_at() RETURNS trigger AS $$
+BEGIN
+    NEW.updated_at = now();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER users_touch_updated_at BEFORE UPDATE ON users
+    FOR EACH ROW WHEN (OLD.* IS DISTINCT FROM NEW.*) EXECUTE FUNCTION touch_updated_at();
+
+CREATE INDEX IF NOT EXISTS users_updated_at_idx ON users (updated_at, id);
+
+CREATE TABLE user_tombstones (
+    user_id INTEGER PRIMARY KEY,
+    deleted_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX user_tombstones_deleted_at_idx ON user_tombstones (deleted_at, user_id);
@@ -0,0 +1,25 @@
+-- cadastro por convite: o código só é mostrado na criação, o banco guarda o hash
+CREATE TABLE invitations (
+    id BIGSERIAL PRIMARY KEY,
+    code_hash CHAR(64) NOT NULL UNIQUE,
+    -- primeiros caracteres do código, para o admin reconhecer o convite na listagem
+    code_hint VARCHAR(8) NOT NULL,
+    note TEXT NOT NULL DEFAULT '',
+    max_uses INTEGER NOT NULL CHECK (max_uses > 0),
+    uses INTEGER NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_by VARCHAR(255) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- user_id fica nulo quando o usuário é removido; o uso continua contando no relatório
+CREATE TABLE invitation_uses (
+    id BIGSERIAL PRIMARY KEY,
+    invitation_id BIGINT NOT NULL REFERENCES invitations (id),
+    user_id INTEGER REFERENCES users (id),
+    used_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX invitation_uses_invitation_idx ON invitation_uses (invitation_id, used_at);
+CREATE INDEX invitation_uses_user_id_idx ON invitation_uses (user_id);
@@ -0,0 +1,17 @@
+-- preenchimento do perfil (0 a 100), mantido pela aplicação a cada alteração dos dados que o compõem
+ALTER TABLE users ADD COLUMN IF NOT EXISTS profile_completeness SMALLINT NOT NULL DEFAULT 0;
+
+-- valor inicial com os mesmos critérios e pesos de usecase.profileCriteria. O backfill não é uma
+-- alteração do usuário: sem desligar o trigger, todos entrariam de novo no feed de /users/changes
+ALTER TABLE users DISABLE TRIGGER users_touch_updated_at;
+
+UPDATE users u SET profile_completeness =
+    (CASE WHEN u.img_url <> '' THEN 25 ELSE 0 END) +
+    (CASE WHEN NOT u.email_undeliverable THEN 25 ELSE 0 END) +
+    (CASE WHEN u.password_hash <> '' THEN 25 ELSE 0 END) +
+    (CASE WHEN EXISTS (SELECT 1 FROM user_preferences p WHERE p.user_id = u.id AND p.locale <> '')
+        THEN 25 ELSE 0 END);
+
+ALTER TABLE users ENABLE TRIGGER users_touch_updated_at;
+
+CREATE INDEX IF NOT EXISTS users_profile_completeness_idx ON users (profile_completeness, id);
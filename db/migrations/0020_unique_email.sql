@@ -0,0 +1,14 @@
+-- um email, uma conta: sem isso o login escolhia uma linha qualquer entre as duplicadas
+DO $$
+DECLARE
+    duplicated TEXT;
+BEGIN
+    SELECT string_agg(email, ', ') INTO duplicated FROM (
+        SELECT lower(email) AS email FROM users GROUP BY lower(email) HAVING count(*) > 1 LIMIT 20
+    ) d;
+    IF duplicated IS NOT NULL THEN
+        RAISE EXCEPTION 'emails com mais de uma conta, resolva antes de migrar (ex.: DELETE /users/:id): %', duplicated;
+    END IF;
+END $$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS users_email_lower_idx ON users (lower(email));
@@ -0,0 +1,2 @@
+-- hash do refresh token anterior à última rotação: apresentá-lo de novo indica que o token vazou
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS previous_refresh_token_hash VARCHAR(64) NOT NULL DEFAULT '';
@@ -0,0 +1,6 @@
+-- cada fila tem os seus workers: a busca do próximo job filtra por fila
+CREATE INDEX IF NOT EXISTS jobs_queue_pending_idx ON jobs (queue, run_at) WHERE status = 'pending';
+DROP INDEX IF EXISTS jobs_pending_idx;
+
+-- reserva vencida de jobs em execução (worker que caiu)
+CREATE INDEX IF NOT EXISTS jobs_running_idx ON jobs (queue, updated_at) WHERE status = 'running';
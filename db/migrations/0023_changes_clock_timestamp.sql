@@ -0,0 +1,11 @@
+-- now() é o início da transação: uma transação longa gravaria updated_at bem antes do
+-- commit, atrás de cursores já entregues. clock_timestamp() é o momento da escrita
+CREATE OR REPLACE FUNCTION touch_updated_at() RETURNS trigger AS $$
+BEGIN
+    NEW.updated_at = clock_timestamp();
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+ALTER TABLE users ALTER COLUMN updated_at SET DEFAULT clock_timestamp();
+ALTER TABLE user_tombstones ALTER COLUMN deleted_at SET DEFAULT clock_timestamp();
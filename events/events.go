@@ -0,0 +1,69 @@
+package events
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	UserCreated     = "user.created"
+	SuspiciousLogin = "user.suspicious_login"
+	PasswordChanged = "user.password_changed"
+	// mudou algum dado que compõe o profile_completeness (avatar, idioma, entregabilidade do email)
+	ProfileUpdated = "user.profile_updated"
+)
+
+type Event struct {
+	Name       string
+	Payload    any
+	OccurredAt time.Time
+}
+
+type Handler func(Event)
+
+// Dispatcher entrega eventos de domínio aos assinantes, cada um em sua goroutine
+type Dispatcher struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{
+		handlers: map[string][]Handler{},
+	}
+}
+
+func (d *Dispatcher) Subscribe(name string, handler Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.handlers[name] = append(d.handlers[name], handler)
+}
+
+func (d *Dispatcher) Publish(name string, payload any) {
+	event := Event{
+		Name:       name,
+		Payload:    payload,
+		OccurredAt: time.Now(),
+	}
+
+	d.mu.RLock()
+	handlers := d.handlers[name]
+	d.mu.RUnlock()
+
+	for _, handler := range handlers {
+		go run(handler, event)
+	}
+}
+
+// um assinante com problema não derruba a aplicação
+func run(handler Handler, event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("events: assinante de %s falhou: %v", event.Name, r)
+		}
+	}()
+
+	handler(event)
+}
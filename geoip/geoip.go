@@ -0,0 +1,59 @@
+package geoip
+
+import (
+	"context"
+	"net"
+
+	"github.com/oschwald/geoip2-golang"
+)
+
+type Location struct {
+	Country string `json:"country"`
+	City    string `json:"city"`
+}
+
+type Resolver interface {
+	Lookup(ip net.IP) (Location, error)
+}
+
+// MaxMindResolver consulta uma base GeoIP2/GeoLite2 City local
+type MaxMindResolver struct {
+	reader *geoip2.Reader
+}
+
+func NewMaxMindResolver(path string) (*MaxMindResolver, error) {
+	reader, err := geoip2.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MaxMindResolver{reader: reader}, nil
+}
+
+func (r *MaxMindResolver) Lookup(ip net.IP) (Location, error) {
+	record, err := r.reader.City(ip)
+	if err != nil {
+		return Location{}, err
+	}
+
+	return Location{
+		Country: record.Country.IsoCode,
+		City:    record.City.Names["en"],
+	}, nil
+}
+
+func (r *MaxMindResolver) Close() error {
+	return r.reader.Close()
+}
+
+type contextKey struct{}
+
+func WithLocation(ctx context.Context, location Location) context.Context {
+	return context.WithValue(ctx, contextKey{}, location)
+}
+
+// FromContext retorna a localização anexada à requisição, se a consulta foi feita
+func FromContext(ctx context.Context) (Location, bool) {
+	location, ok := ctx.Value(contextKey{}).(Location)
+	return location, ok
+}
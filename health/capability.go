@@ -0,0 +1,60 @@
+package health
+
+// Capability é uma dependência opcional. Sem ela, os recursos listados ficam desativados
+// ou usam o fallback, e o núcleo da API (cadastro e consulta de usuários) segue
+// funcionando apenas com o Postgres. Configurada mas fora do ar, o serviço fica
+// degradado: a falha não impede o boot nem a prontidão
+type Capability struct {
+	Name string
+	// a dependência está configurada; só então Check entra no boot e no /readyz
+	Enabled bool
+	// recursos que dependem dela
+	Features []string
+	// comportamento quando não está configurada. Configurada e fora do ar, a dependência
+	// continua em uso (degradada) até voltar
+	Fallback string
+	// verificação da dependência; Name vazio usa o nome da capacidade
+	Check Check
+}
+
+// CheckName é o nome da verificação da capacidade no relatório
+func (c Capability) CheckName() string {
+	if c.Check.Name != "" {
+		return c.Check.Name
+	}
+	return c.Name
+}
+
+// RegisterCapability registra a capacidade e, se habilitada, a sua verificação
+func (r *Registry) RegisterCapability(capabilities ...Capability) {
+	for _, capability := range capabilities {
+		if capability.Enabled && capability.Check.Run != nil {
+			check := capability.Check
+			check.Name = capability.CheckName()
+			check.Optional = true
+			r.Register(check)
+		}
+
+		r.mu.Lock()
+		r.capabilities = append(r.capabilities, capability)
+		r.mu.Unlock()
+	}
+}
+
+func (r *Registry) Capabilities() []Capability {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]Capability{}, r.capabilities...)
+}
+
+// Disabled lista as capacidades não configuradas, para o log do boot
+func (r *Registry) Disabled() []Capability {
+	var disabled []Capability
+	for _, capability := range r.Capabilities() {
+		if !capability.Enabled {
+			disabled = append(disabled, capability)
+		}
+	}
+	return disabled
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package health
+
+import "context"
+
+// DiskSpace não é suportado nesta plataforma; a verificação sempre passa
+func DiskSpace(dir string, minFree uint64) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return nil
+	}
+}
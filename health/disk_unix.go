@@ -0,0 +1,25 @@
+//go:build unix
+
+package health
+
+import (
+	"context"
+	"fmt"
+	"syscall"
+)
+
+// DiskSpace falha quando o sistema de arquivos de dir tem menos que minFree bytes livres
+func DiskSpace(dir string, minFree uint64) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		var stat syscall.Statfs_t
+		if err := syscall.Statfs(dir, &stat); err != nil {
+			return err
+		}
+
+		free := uint64(stat.Bavail) * uint64(stat.Bsize)
+		if free < minFree {
+			return fmt.Errorf("%d MiB livres, mínimo %d MiB", free>>20, minFree>>20)
+		}
+		return nil
+	}
+}
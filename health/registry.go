@@ -0,0 +1,68 @@
+package health
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Registry reúne as verificações de prontidão; cada subsistema registra a sua
+// ao ser montado e o /readyz executa todas
+type Registry struct {
+	mu             sync.RWMutex
+	checks         []Check
+	capabilities   []Capability
+	defaultTimeout time.Duration
+
+	// último resultado do RunCached; runMu também serializa as execuções
+	runMu   sync.Mutex
+	last    Report
+	lastRun time.Time
+}
+
+func NewRegistry(defaultTimeout time.Duration) *Registry {
+	return &Registry{
+		defaultTimeout: defaultTimeout,
+	}
+}
+
+func (r *Registry) Register(checks ...Check) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.checks = append(r.checks, checks...)
+}
+
+func (r *Registry) Checks() []Check {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return append([]Check{}, r.checks...)
+}
+
+// Run executa cada verificação uma única vez, em paralelo e sem novas tentativas
+func (r *Registry) Run(ctx context.Context) Report {
+	return RunStartupChecks(ctx, r.Checks(), 1, 0, r.defaultTimeout)
+}
+
+// RunCached reaproveita o último relatório por até ttl. Chamadas simultâneas esperam a
+// execução em andamento em vez de disparar outra, e o cancelamento de quem chamou
+// primeiro não contamina o resultado das demais
+func (r *Registry) RunCached(ctx context.Context, ttl time.Duration) Report {
+	r.runMu.Lock()
+	defer r.runMu.Unlock()
+
+	if r.last != nil && time.Since(r.lastRun) < ttl {
+		return r.last
+	}
+
+	r.last = r.Run(context.WithoutCancel(ctx))
+	r.lastRun = time.Now()
+	for _, result := range r.last {
+		if result.Err != nil {
+			log.Printf("health: %s falhou: %v (%s)", result.Name, result.Err, result.Hint)
+		}
+	}
+	return r.last
+}
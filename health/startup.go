@@ -0,0 +1,128 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Check verifica uma dependência externa. Hint orienta o operador quando ela falha
+type Check struct {
+	Name string
+	Hint string
+	// dependência opcional (Capability): a falha degrada o serviço, mas não impede o
+	// boot nem a prontidão
+	Optional bool
+	// prazo de cada execução; zero usa o padrão de quem executa
+	Timeout time.Duration
+	Run     func(ctx context.Context) error
+}
+
+type Result struct {
+	Name     string
+	Hint     string
+	Optional bool
+	Err      error
+	Attempts int
+	Duration time.Duration
+}
+
+type Report []Result
+
+// Failed indica falha de alguma dependência obrigatória
+func (r Report) Failed() bool {
+	for _, result := range r {
+		if result.Err != nil && !result.Optional {
+			return true
+		}
+	}
+	return false
+}
+
+// Degraded lista as dependências opcionais que falharam
+func (r Report) Degraded() []string {
+	var names []string
+	for _, result := range r {
+		if result.Err != nil && result.Optional {
+			names = append(names, result.Name)
+		}
+	}
+	return names
+}
+
+func (r Report) String() string {
+	var b strings.Builder
+	b.WriteString("verificação de dependências:\n")
+
+	for _, result := range r {
+		if result.Err == nil {
+			fmt.Fprintf(&b, "  [ok]    %s (%d tentativa(s), %s)\n",
+				result.Name, result.Attempts, result.Duration.Round(time.Millisecond))
+			continue
+		}
+
+		status := "[falha]"
+		if result.Optional {
+			status = "[degr.]"
+		}
+		fmt.Fprintf(&b, "  %s %s (%d tentativa(s)): %v\n", status, result.Name, result.Attempts, result.Err)
+		if result.Hint != "" {
+			fmt.Fprintf(&b, "          -> %s\n", result.Hint)
+		}
+	}
+
+	return b.String()
+}
+
+// RunStartupChecks executa as verificações em paralelo, cada uma com até
+// `attempts` tentativas e backoff exponencial a partir de `backoff`
+func RunStartupChecks(ctx context.Context, checks []Check, attempts int, backoff, timeout time.Duration) Report {
+	report := make(Report, len(checks))
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check Check) {
+			defer wg.Done()
+			report[i] = runWithRetry(ctx, check, attempts, backoff, timeout)
+		}(i, check)
+	}
+	wg.Wait()
+
+	return report
+}
+
+func runWithRetry(ctx context.Context, check Check, attempts int, backoff, timeout time.Duration) Result {
+	result := Result{Name: check.Name, Hint: check.Hint, Optional: check.Optional}
+	if check.Timeout > 0 {
+		timeout = check.Timeout
+	}
+	start := time.Now()
+	wait := backoff
+
+	for result.Attempts < attempts {
+		result.Attempts++
+
+		attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+		result.Err = check.Run(attemptCtx)
+		cancel()
+
+		if result.Err == nil || result.Attempts == attempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			result.Err = ctx.Err()
+			result.Duration = time.Since(start)
+			return result
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+
+	result.Duration = time.Since(start)
+	return result
+}
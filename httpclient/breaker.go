@@ -0,0 +1,86 @@
+package httpclient
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pytsx/goapi/metrics"
+)
+
+// breaker abre após threshold falhas seguidas e recusa as chamadas durante o cooldown.
+// Depois dele, deixa passar uma única chamada de teste: sucesso fecha o circuito,
+// falha o abre de novo
+type breaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newBreaker(name string, threshold int, cooldown time.Duration) *breaker {
+	metrics.OutboundCircuitOpen.WithLabelValues(name).Set(0)
+	return &breaker{
+		name:      name,
+		threshold: threshold,
+		cooldown:  cooldown,
+	}
+}
+
+func (b *breaker) allow() bool {
+	if b.threshold <= 0 {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openedAt.IsZero() {
+		return true
+	}
+	if b.probing || time.Since(b.openedAt) < b.cooldown {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+func (b *breaker) record(failure bool) {
+	if b.threshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !failure {
+		b.failures = 0
+		b.probing = false
+		if !b.openedAt.IsZero() {
+			b.openedAt = time.Time{}
+			metrics.OutboundCircuitOpen.WithLabelValues(b.name).Set(0)
+		}
+		return
+	}
+
+	b.failures++
+	if b.probing || b.failures >= b.threshold {
+		b.probing = false
+		b.openedAt = time.Now()
+		metrics.OutboundCircuitOpen.WithLabelValues(b.name).Set(1)
+	}
+}
+
+// release libera a chamada de teste sem mudar o estado do circuito
+func (b *breaker) release() {
+	if b.threshold <= 0 {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
@@ -0,0 +1,161 @@
+// Package httpclient é o cliente HTTP compartilhado das integrações externas (moderação,
+// classificador de imagens, CAPTCHA, confirmação de webhooks): prazo por tentativa, novas
+// tentativas com backoff, circuit breaker, propagação do traceparent e métricas por cliente
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/pytsx/goapi/metrics"
+	"github.com/pytsx/goapi/tracing"
+)
+
+var ErrCircuitOpen = errors.New("circuito aberto após falhas seguidas, chamada não enviada")
+
+// Config é compartilhada pelas integrações; cada uma ajusta o prazo e as tentativas
+// conforme o serviço que chama
+type Config struct {
+	// prazo de cada tentativa; o contexto da requisição limita o total
+	Timeout time.Duration
+	// novas tentativas após a primeira, apenas para falhas transitórias
+	Retries int
+	// espera antes da primeira nova tentativa, dobrada a cada uma, com jitter
+	Backoff time.Duration
+	// falhas seguidas que abrem o circuito; 0 desativa o circuit breaker
+	BreakerThreshold int
+	// tempo com o circuito aberto antes de deixar passar uma chamada de teste
+	BreakerCooldown time.Duration
+}
+
+func (c Config) WithTimeout(timeout time.Duration) Config {
+	c.Timeout = timeout
+	return c
+}
+
+func (c Config) WithRetries(retries int) Config {
+	c.Retries = retries
+	return c
+}
+
+type Client struct {
+	name    string
+	cfg     Config
+	http    *http.Client
+	breaker *breaker
+}
+
+// New cria o cliente de uma integração; name identifica o cliente nas métricas e nos erros
+func New(name string, cfg Config) *Client {
+	return &Client{
+		name:    name,
+		cfg:     cfg,
+		http:    &http.Client{Timeout: cfg.Timeout},
+		breaker: newBreaker(name, cfg.BreakerThreshold, cfg.BreakerCooldown),
+	}
+}
+
+// Do envia a requisição como http.Client.Do. Só repete quando é seguro: falhas de rede
+// e respostas 429, 502, 503 e 504, e desde que o corpo possa ser reenviado (GetBody,
+// preenchido por http.NewRequest para bytes.Reader e strings.Reader)
+func (c *Client) Do(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	if parent, ok := tracing.FromContext(ctx); ok {
+		req.Header.Set("traceparent", parent.Child())
+	}
+
+	for attempt := 0; ; attempt++ {
+		if !c.breaker.allow() {
+			return nil, fmt.Errorf("%s: %w", c.name, ErrCircuitOpen)
+		}
+
+		start := time.Now()
+		resp, err := c.http.Do(req)
+		if ctx.Err() != nil {
+			// cancelado pelo chamador: não diz nada sobre a saúde do serviço
+			c.breaker.release()
+		} else {
+			// erros 4xx são do pedido, não do serviço
+			c.breaker.record(err != nil || resp.StatusCode >= 500)
+		}
+
+		outcome := "error"
+		if err == nil {
+			outcome = strconv.Itoa(resp.StatusCode)
+		}
+		c.observe(ctx, outcome, time.Since(start))
+
+		if attempt >= c.cfg.Retries || !retryable(ctx, resp, err) {
+			return resp, err
+		}
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, err
+			}
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return resp, err
+			}
+			req.Body = body
+		}
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+			resp.Body.Close()
+		}
+
+		metrics.OutboundRetries.WithLabelValues(c.name).Inc()
+		if err := sleep(ctx, c.backoff(attempt)); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// backoff exponencial com jitter, para as réplicas não repetirem ao mesmo tempo
+func (c *Client) backoff(attempt int) time.Duration {
+	wait := c.cfg.Backoff << attempt
+	if wait <= 0 {
+		return 0
+	}
+	return wait/2 + rand.N(wait/2+1)
+}
+
+func (c *Client) observe(ctx context.Context, outcome string, elapsed time.Duration) {
+	var traceID string
+	if parent, ok := tracing.FromContext(ctx); ok && parent.Sampled {
+		traceID = parent.TraceID
+	}
+	metrics.ObserveWithTrace(metrics.OutboundDuration.WithLabelValues(c.name, outcome), elapsed.Seconds(), traceID)
+}
+
+// retryable indica falha transitória. Cancelamento ou prazo do chamador encerram as tentativas
+func retryable(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func sleep(ctx context.Context, wait time.Duration) error {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
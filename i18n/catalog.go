@@ -0,0 +1,54 @@
+package i18n
+
+var catalog = map[string]map[string]string{
+	"pt-BR": {
+		"format.datetime": "02/01/2006 15:04",
+
+		"email.welcome.subject": "Bem-vindo(a)!",
+		"email.welcome.body":    "Olá, %s.\n\nSua conta foi criada com sucesso.",
+
+		"email.login_alert.subject": "Novo acesso à sua conta: foi você?",
+		"email.login_alert.body": "Olá, %s.\n\n" +
+			"Detectamos um novo acesso à sua conta:\n\n" +
+			"Dispositivo: %s\nLocal: %s\nIP: %s\nData: %s\n\n" +
+			"Se foi você, nenhuma ação é necessária. Caso contrário, altere sua senha imediatamente.",
+
+		"email.password_changed.subject": "Sua senha foi alterada",
+		"email.password_changed.body": "Olá, %s.\n\n" +
+			"A senha da sua conta foi alterada em %s (IP %s) e as demais sessões foram encerradas.\n\n" +
+			"Se não foi você, entre em contato com o suporte imediatamente.",
+
+		"moderation.status.approved": "Aprovado",
+		"moderation.status.flagged":  "Em revisão",
+		"moderation.status.rejected": "Reprovado",
+
+		"avatar.status.none":        "Sem avatar",
+		"avatar.status.approved":    "Aprovado",
+		"avatar.status.quarantined": "Em quarentena",
+	},
+	"en": {
+		"format.datetime": "Jan 2, 2006 3:04 PM",
+
+		"email.welcome.subject": "Welcome!",
+		"email.welcome.body":    "Hi %s,\n\nYour account has been created successfully.",
+
+		"email.login_alert.subject": "New sign-in to your account: was it you?",
+		"email.login_alert.body": "Hi %s,\n\n" +
+			"We noticed a new sign-in to your account:\n\n" +
+			"Device: %s\nLocation: %s\nIP: %s\nDate: %s\n\n" +
+			"If this was you, no action is needed. Otherwise, change your password immediately.",
+
+		"email.password_changed.subject": "Your password was changed",
+		"email.password_changed.body": "Hi %s,\n\n" +
+			"Your account password was changed on %s (IP %s) and your other sessions were signed out.\n\n" +
+			"If this wasn't you, contact support immediately.",
+
+		"moderation.status.approved": "Approved",
+		"moderation.status.flagged":  "Under review",
+		"moderation.status.rejected": "Rejected",
+
+		"avatar.status.none":        "No avatar",
+		"avatar.status.approved":    "Approved",
+		"avatar.status.quarantined": "Quarantined",
+	},
+}
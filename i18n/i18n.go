@@ -0,0 +1,75 @@
+// Package i18n traduz o conteúdo gerado pela API (emails, rótulos de status)
+// a partir de um catálogo por idioma. pt-BR é o idioma padrão e o fallback
+// de qualquer chave sem tradução.
+package i18n
+
+import (
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+const Default = "pt-BR"
+
+var (
+	supported = []language.Tag{language.BrazilianPortuguese, language.English}
+	matcher   = language.NewMatcher(supported)
+)
+
+// Match escolhe o idioma suportado mais próximo de um header Accept-Language
+// ou de um locale salvo (ex.: "en-US" => "en"). Vazio ou desconhecido resulta no padrão.
+func Match(preferences ...string) string {
+	for _, preference := range preferences {
+		if preference == "" {
+			continue
+		}
+
+		tags, _, err := language.ParseAcceptLanguage(preference)
+		if err != nil || len(tags) == 0 {
+			continue
+		}
+
+		_, index, confidence := matcher.Match(tags...)
+		if confidence != language.No {
+			return supported[index].String()
+		}
+	}
+	return Default
+}
+
+// Normalize devolve o locale suportado correspondente a uma tag BCP 47 em qualquer caixa
+// ou região (ex.: "pt-br" => "pt-BR", "en-US" => "en"). ok é falso se nenhum corresponde.
+func Normalize(locale string) (normalized string, ok bool) {
+	tag, err := language.Parse(locale)
+	if err != nil {
+		return "", false
+	}
+
+	_, index, confidence := matcher.Match(tag)
+	if confidence == language.No {
+		return "", false
+	}
+	return supported[index].String(), true
+}
+
+// Supported informa se o locale tem um catálogo próprio
+func Supported(locale string) bool {
+	_, ok := catalog[locale]
+	return ok
+}
+
+// T traduz a chave para o locale, formatando os argumentos como fmt.Sprintf
+func T(locale, key string, args ...any) string {
+	message, ok := catalog[locale][key]
+	if !ok {
+		message, ok = catalog[Default][key]
+	}
+	if !ok {
+		return key
+	}
+
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
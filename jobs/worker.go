@@ -0,0 +1,149 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/repository"
+)
+
+type Handler func(ctx context.Context, payload json.RawMessage) error
+
+// Worker consome a tabela jobs, repetindo as falhas com backoff até esgotar as tentativas.
+// Cada fila tem os seus próprios loops, para que uma fila lenta não bloqueie as outras
+type Worker struct {
+	repository   repository.JobRepository
+	handlers     map[string]Handler
+	queues       map[string]int
+	pollInterval time.Duration
+	retryBackoff time.Duration
+	lease        time.Duration
+
+	wg sync.WaitGroup
+}
+
+// NewWorker recebe o número de loops por fila (ex.: {"default": 2, "tasks": 1})
+func NewWorker(repo repository.JobRepository, queues map[string]int, pollInterval, retryBackoff, lease time.Duration) *Worker {
+	return &Worker{
+		repository:   repo,
+		handlers:     map[string]Handler{},
+		queues:       queues,
+		pollInterval: pollInterval,
+		retryBackoff: retryBackoff,
+		lease:        lease,
+	}
+}
+
+func (w *Worker) Handle(kind string, handler Handler) {
+	w.handlers[kind] = handler
+}
+
+// Start processa os jobs até o contexto ser cancelado. O job em andamento recebe o
+// cancelamento e volta para a fila; Wait espera esse retorno
+func (w *Worker) Start(ctx context.Context) {
+	for queue, concurrency := range w.queues {
+		for range max(concurrency, 1) {
+			w.wg.Add(1)
+			go w.loop(ctx, queue)
+		}
+	}
+}
+
+func (w *Worker) loop(ctx context.Context, queue string) {
+	defer w.wg.Done()
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		processed, err := w.processNext(ctx, queue)
+		if err != nil {
+			log.Printf("jobs (%s): %v", queue, err)
+		}
+
+		// fila vazia ou erro: espera antes de consultar de novo
+		if !processed || err != nil {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(w.pollInterval):
+			}
+		}
+	}
+}
+
+func (w *Worker) Wait() {
+	w.wg.Wait()
+}
+
+func (w *Worker) processNext(ctx context.Context, queue string) (bool, error) {
+	job, err := w.repository.ClaimNext(queue, w.lease)
+	if err != nil || job == nil {
+		return false, err
+	}
+
+	stop := w.keepLease(job.ID)
+	err = w.run(ctx, job)
+	stop()
+	if err == nil {
+		return true, w.repository.Complete(job.ID)
+	}
+
+	if ctx.Err() != nil {
+		// interrompido pelo encerramento: volta para a fila sem esperar o backoff
+		log.Printf("jobs: %s #%d interrompido pelo encerramento, devolvido à fila", job.Kind, job.ID)
+		now := time.Now()
+		return true, w.repository.Fail(job.ID, err.Error(), &now)
+	}
+
+	if job.Attempts >= job.MaxAttempts {
+		log.Printf("jobs: %s #%d movido para a fila morta: %v", job.Kind, job.ID, err)
+		return true, w.repository.Fail(job.ID, err.Error(), nil)
+	}
+
+	retryAt := time.Now().Add(w.retryBackoff * time.Duration(job.Attempts*job.Attempts))
+	return true, w.repository.Fail(job.ID, err.Error(), &retryAt)
+}
+
+// keepLease renova a reserva do job a cada terço do lease, para que um job longo
+// não seja retomado por outro worker enquanto ainda executa
+func (w *Worker) keepLease(id int64) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(w.lease / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := w.repository.Heartbeat(id); err != nil {
+					log.Printf("jobs: renovando a reserva do job #%d: %v", id, err)
+				}
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+func (w *Worker) run(ctx context.Context, job *model.Job) (err error) {
+	handler, ok := w.handlers[job.Kind]
+	if !ok {
+		return fmt.Errorf("nenhum handler para jobs do tipo %s", job.Kind)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return handler(ctx, job.Payload)
+}
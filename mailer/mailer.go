@@ -0,0 +1,89 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"strings"
+)
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+type SMTPMailer struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+func NewSMTPMailer(addr, username, password, from string) SMTPMailer {
+	var auth smtp.Auth
+	if username != "" {
+		host, _, _ := net.SplitHostPort(addr)
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return SMTPMailer{
+		addr: addr,
+		from: from,
+		auth: auth,
+	}
+}
+
+func (m SMTPMailer) Send(ctx context.Context, msg Message) error {
+	body := strings.Join([]string{
+		"From: " + m.from,
+		"To: " + msg.To,
+		"Subject: " + msg.Subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		msg.Body,
+	}, "\r\n")
+
+	if err := smtp.SendMail(m.addr, m.auth, m.from, []string{msg.To}, []byte(body)); err != nil {
+		return fmt.Errorf("enviando email para %s: %w", msg.To, err)
+	}
+	return nil
+}
+
+// LogMailer apenas registra os emails no log, usado quando não há SMTP configurado
+type LogMailer struct{}
+
+func (LogMailer) Send(ctx context.Context, msg Message) error {
+	log.Printf("mailer: para=%s assunto=%q", msg.To, msg.Subject)
+	return nil
+}
+
+// Ping abre uma conexão com o servidor SMTP e encerra a sessão sem enviar nada
+func (m SMTPMailer) Ping(ctx context.Context) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", m.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	// o prazo do dial não vale para a saudação e o QUIT: um servidor que aceita a
+	// conexão e não responde travaria a verificação
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	host, _, _ := net.SplitHostPort(m.addr)
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
@@ -0,0 +1,37 @@
+package mailer
+
+import (
+	"context"
+	"log"
+)
+
+type SuppressionChecker interface {
+	IsSuppressed(email string) (bool, error)
+}
+
+// SuppressingMailer descarta envios para endereços que geraram bounce ou reclamação
+type SuppressingMailer struct {
+	next    Mailer
+	checker SuppressionChecker
+}
+
+func NewSuppressingMailer(next Mailer, checker SuppressionChecker) SuppressingMailer {
+	return SuppressingMailer{
+		next:    next,
+		checker: checker,
+	}
+}
+
+func (m SuppressingMailer) Send(ctx context.Context, msg Message) error {
+	suppressed, err := m.checker.IsSuppressed(msg.To)
+	if err != nil {
+		return err
+	}
+
+	if suppressed {
+		log.Printf("mailer: envio para %s suprimido", msg.To)
+		return nil
+	}
+
+	return m.next.Send(ctx, msg)
+}
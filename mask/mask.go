@@ -0,0 +1,274 @@
+// Package mask serializa respostas ocultando campos sensíveis conforme quem
+// faz a requisição. Os campos são marcados com a tag `mask`:
+//
+//	Email string `json:"email" mask:"owner"`       // dono do registro e privilegiados
+//	Notes string `json:"notes" mask:"privileged"`  // apenas privilegiados (admins, contas de serviço)
+//
+// O dono é identificado pelo método OwnerID do registro (ou do registro que o contém).
+package mask
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+const (
+	Owner      = "owner"
+	Privileged = "privileged"
+)
+
+// Viewer descreve quem vai receber a resposta
+type Viewer struct {
+	// usuário autenticado; zero para chamadas anônimas
+	UserID     int
+	Privileged bool
+}
+
+// Owned é implementado pelos registros que pertencem a um usuário
+type Owned interface {
+	OwnerID() int
+}
+
+// Apply devolve uma representação de v, pronta para ctx.JSON, sem os campos
+// que o viewer não pode ver. Tipos sem a tag `mask` são serializados como de costume.
+func Apply(v any, viewer Viewer) any {
+	return apply(reflect.ValueOf(v), viewer, 0)
+}
+
+var (
+	marshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	ownedType     = reflect.TypeOf((*Owned)(nil)).Elem()
+)
+
+func apply(value reflect.Value, viewer Viewer, owner int) any {
+	if !value.IsValid() {
+		return nil
+	}
+	if value.Type().Implements(ownedType) && (value.Kind() != reflect.Pointer || !value.IsNil()) {
+		owner = value.Interface().(Owned).OwnerID()
+	}
+	if value.Type().Implements(marshalerType) {
+		return value.Interface()
+	}
+
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if value.IsNil() {
+			return nil
+		}
+		return apply(value.Elem(), viewer, owner)
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return nil
+		}
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			return value.Interface()
+		}
+		items := make([]any, value.Len())
+		for i := range items {
+			items[i] = apply(value.Index(i), viewer, owner)
+		}
+		return items
+	case reflect.Struct:
+		obj := object{}
+		appendFields(&obj, value, viewer, owner)
+		return obj
+	default:
+		return value.Interface()
+	}
+}
+
+// fieldPlan guarda o que appendFields precisa saber de cada campo, calculado uma
+// vez por tipo: ler tags e serializar nomes a cada resposta dominava o custo
+type fieldPlan struct {
+	index int
+	// nome já serializado como string JSON, com as aspas
+	name      []byte
+	level     string
+	omitEmpty bool
+	// struct embutida sem nome, cujos campos sobem para o objeto de fora
+	inline bool
+}
+
+var plans sync.Map // reflect.Type => []fieldPlan
+
+func planFor(t reflect.Type) []fieldPlan {
+	if cached, ok := plans.Load(t); ok {
+		return cached.([]fieldPlan)
+	}
+
+	var fields []fieldPlan
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+
+		inline := field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct
+		if name == "" {
+			name = field.Name
+		}
+		quoted, _ := json.Marshal(name)
+
+		fields = append(fields, fieldPlan{
+			index:     i,
+			name:      quoted,
+			level:     field.Tag.Get("mask"),
+			omitEmpty: strings.Contains(options, "omitempty"),
+			inline:    inline,
+		})
+	}
+
+	cached, _ := plans.LoadOrStore(t, fields)
+	return cached.([]fieldPlan)
+}
+
+// appendFields segue as regras do encoding/json para nomes, omitempty e structs embutidas
+func appendFields(obj *object, value reflect.Value, viewer Viewer, owner int) {
+	for _, field := range planFor(value.Type()) {
+		if !visible(field.level, viewer, owner) {
+			continue
+		}
+
+		fieldValue := value.Field(field.index)
+		if field.inline {
+			appendFields(obj, fieldValue, viewer, owner)
+			continue
+		}
+		if field.omitEmpty && isEmpty(fieldValue) {
+			continue
+		}
+
+		*obj = append(*obj, member{name: field.name, value: apply(fieldValue, viewer, owner)})
+	}
+}
+
+func visible(level string, viewer Viewer, owner int) bool {
+	switch level {
+	case "":
+		return true
+	case Owner:
+		return viewer.Privileged || (viewer.UserID != 0 && viewer.UserID == owner)
+	default:
+		return viewer.Privileged
+	}
+}
+
+func isEmpty(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return value.Len() == 0
+	case reflect.Struct:
+		return false
+	default:
+		return value.IsZero()
+	}
+}
+
+type member struct {
+	name  []byte
+	value any
+}
+
+// object mantém a ordem de declaração dos campos, ao contrário de um map
+type object []member
+
+// MarshalJSON escreve strings, inteiros e booleanos diretamente; os demais valores
+// (datas, objetos aninhados) passam pelo encoding/json
+func (o object) MarshalJSON() ([]byte, error) {
+	buf := make([]byte, 0, 64*len(o))
+	buf = append(buf, '{')
+	for i, m := range o {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, m.name...)
+		buf = append(buf, ':')
+
+		switch value := m.value.(type) {
+		case string:
+			buf = appendString(buf, value)
+		case int:
+			buf = strconv.AppendInt(buf, int64(value), 10)
+		case int64:
+			buf = strconv.AppendInt(buf, value, 10)
+		case bool:
+			buf = strconv.AppendBool(buf, value)
+		case nil:
+			buf = append(buf, "null"...)
+		default:
+			encoded, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+			buf = append(buf, encoded...)
+		}
+	}
+	return append(buf, '}'), nil
+}
+
+const hex = "0123456789abcdef"
+
+// appendString produz o mesmo resultado de json.Marshal para strings, inclusive o
+// escape de <, > e & e a troca de UTF-8 inválido por U+FFFD
+func appendString(buf []byte, s string) []byte {
+	buf = append(buf, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if c >= 0x20 && c != '"' && c != '\\' && c != '<' && c != '>' && c != '&' {
+				i++
+				continue
+			}
+			buf = append(buf, s[start:i]...)
+			switch c {
+			case '"', '\\':
+				buf = append(buf, '\\', c)
+			case '\n':
+				buf = append(buf, '\\', 'n')
+			case '\r':
+				buf = append(buf, '\\', 'r')
+			case '\t':
+				buf = append(buf, '\\', 't')
+			case '\b':
+				buf = append(buf, '\\', 'b')
+			case '\f':
+				buf = append(buf, '\\', 'f')
+			default:
+				buf = append(buf, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			buf = append(buf, s[start:i]...)
+			buf = append(buf, "\uFFFD"...)
+			i += size
+			start = i
+			continue
+		}
+		if r == '\u2028' || r == '\u2029' {
+			buf = append(buf, s[start:i]...)
+			buf = append(buf, '\\', 'u', '2', '0', '2', hex[r&0xF])
+			i += size
+			start = i
+			continue
+		}
+		i += size
+	}
+	buf = append(buf, s[start:]...)
+	return append(buf, '"')
+}
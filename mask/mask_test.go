@@ -0,0 +1,108 @@
+package mask
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestAppendString(t *testing.T) {
+	tests := []struct {
+		name, in string
+	}{
+		{"vazia", ""},
+		{"ascii", "Ana Maria"},
+		{"aspas e barra", `a "b" \c`},
+		{"escapes curtos", "\n\r\t\b\f"},
+		{"controles", "\x00\x01\x1f\x7f"},
+		{"html", "<script>a && b</script>"},
+		{"acentos", "João Ação"},
+		{"separadores de linha", "a\u2028b\u2029c"},
+		{"utf-8 inválido", "a\xffb\xc3"},
+		{"sequência truncada", "\xe2\x80"},
+		{"emoji", "olá 👋"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want, err := json.Marshal(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := appendString(nil, tt.in); string(got) != string(want) {
+				t.Errorf("appendString(%q) = %s, esperado %s", tt.in, got, want)
+			}
+		})
+	}
+}
+
+func FuzzAppendString(f *testing.F) {
+	for _, seed := range []string{"", "a\"b\\c", "\x00\n ", "<>&", "\u2028", "\xff\xfe", "João"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, in string) {
+		want, err := json.Marshal(in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := appendString(nil, in); string(got) != string(want) {
+			t.Errorf("appendString(%q) = %s, esperado %s", in, got, want)
+		}
+	})
+}
+
+type note struct {
+	Text string `json:"text"`
+}
+
+type account struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Email   string `json:"email" mask:"owner"`
+	Notes   string `json:"notes,omitempty" mask:"privileged"`
+	Private *note  `json:"private,omitempty" mask:"owner"`
+}
+
+func (a account) OwnerID() int { return a.ID }
+
+type team struct {
+	Name    string    `json:"name"`
+	Members []account `json:"members"`
+}
+
+func TestApply(t *testing.T) {
+	ana := account{ID: 1, Name: "Ana", Email: "ana@example.com", Notes: "vip", Private: &note{Text: "x"}}
+	tests := []struct {
+		name   string
+		in     any
+		viewer Viewer
+		want   string
+	}{
+		{"anônimo", ana, Viewer{},
+			`{"id":1,"name":"Ana"}`},
+		{"outro usuário", ana, Viewer{UserID: 2},
+			`{"id":1,"name":"Ana"}`},
+		{"dono", ana, Viewer{UserID: 1},
+			`{"id":1,"name":"Ana","email":"ana@example.com","private":{"text":"x"}}`},
+		{"privilegiado", ana, Viewer{UserID: 2, Privileged: true},
+			`{"id":1,"name":"Ana","email":"ana@example.com","notes":"vip","private":{"text":"x"}}`},
+		{"ponteiro", &ana, Viewer{UserID: 1},
+			`{"id":1,"name":"Ana","email":"ana@example.com","private":{"text":"x"}}`},
+		{"lista com dono por item", []account{ana, {ID: 2, Name: "Bia", Email: "bia@example.com"}}, Viewer{UserID: 2},
+			`[{"id":1,"name":"Ana"},{"id":2,"name":"Bia","email":"bia@example.com"}]`},
+		{"aninhado sem dono", team{Name: "t", Members: []account{ana}}, Viewer{UserID: 1},
+			`{"name":"t","members":[{"id":1,"name":"Ana","email":"ana@example.com","private":{"text":"x"}}]}`},
+		{"nil", nil, Viewer{}, `null`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(Apply(tt.in, tt.viewer))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Apply(%+v, %+v) = %s, esperado %s", tt.in, tt.viewer, got, tt.want)
+			}
+		})
+	}
+}
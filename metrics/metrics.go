@@ -0,0 +1,83 @@
+// Package metrics concentra as métricas Prometheus da API, expostas em /metrics
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var registry = prometheus.NewRegistry()
+
+var (
+	// DeprecatedRequests conta as chamadas a rotas e parâmetros marcados como obsoletos
+	DeprecatedRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "goapi_deprecated_requests_total",
+		Help: "Requisições a rotas ou parâmetros obsoletos.",
+	}, []string{"method", "route", "param"})
+
+	BulkheadInFlight = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "goapi_bulkhead_in_flight",
+		Help: "Requisições em andamento em cada bulkhead.",
+	}, []string{"bulkhead"})
+
+	BulkheadRejected = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "goapi_bulkhead_rejected_total",
+		Help: "Requisições recusadas por falta de vaga no bulkhead.",
+	}, []string{"bulkhead"})
+
+	RequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "goapi_http_request_duration_seconds",
+		Help:    "Latência das requisições HTTP, por rota e status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	// OutboundDuration mede cada tentativa das chamadas do httpclient; status é o código
+	// HTTP ou "error" para falhas de rede e prazo
+	OutboundDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "goapi_outbound_request_duration_seconds",
+		Help:    "Latência das chamadas a serviços externos, por cliente e status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"client", "status"})
+
+	OutboundRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "goapi_outbound_retries_total",
+		Help: "Novas tentativas de chamadas a serviços externos.",
+	}, []string{"client"})
+
+	OutboundCircuitOpen = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "goapi_outbound_circuit_open",
+		Help: "1 enquanto o circuit breaker do cliente está aberto.",
+	}, []string{"client"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		DeprecatedRequests,
+		BulkheadInFlight,
+		BulkheadRejected,
+		RequestDuration,
+		OutboundDuration,
+		OutboundRetries,
+		OutboundCircuitOpen,
+	)
+}
+
+// Handler serve as métricas no formato de exposição do Prometheus. Exemplares só aparecem
+// quando o scraper negocia OpenMetrics (Accept: application/openmetrics-text)
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{EnableOpenMetrics: true})
+}
+
+// ObserveWithTrace registra o valor e, havendo traceID, anexa-o como exemplar
+func ObserveWithTrace(observer prometheus.Observer, value float64, traceID string) {
+	if exemplar, ok := observer.(prometheus.ExemplarObserver); ok && traceID != "" {
+		exemplar.ObserveWithExemplar(value, prometheus.Labels{"trace_id": traceID})
+		return
+	}
+	observer.Observe(value)
+}
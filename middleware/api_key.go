@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/model"
+)
+
+const (
+	APIKeyHeader = "X-API-Key"
+	adminKey     = "admin"
+)
+
+// IdentifyAdmin marca a requisição como administrativa quando traz uma chave
+// válida, sem bloquear as demais; consulte com IsAdmin
+func IdentifyAdmin(keys []string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if provided := ctx.GetHeader(APIKeyHeader); provided != "" && validAPIKey(keys, provided) {
+			ctx.Set(adminKey, true)
+		}
+		ctx.Next()
+	}
+}
+
+func IsAdmin(ctx *gin.Context) bool {
+	return ctx.GetBool(adminKey)
+}
+
+// RequireAPIKey libera a rota apenas para requisições com uma das chaves configuradas
+func RequireAPIKey(keys []string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		provided := ctx.GetHeader(APIKeyHeader)
+
+		if provided == "" || !validAPIKey(keys, provided) {
+			response := model.Response{
+				Message: "Chave de API ausente ou inválida",
+			}
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response)
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+func validAPIKey(keys []string, provided string) bool {
+	for _, key := range keys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(provided)) == 1 {
+			return true
+		}
+	}
+	return false
+}
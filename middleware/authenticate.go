@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/auth"
+	"github.com/pytsx/goapi/model"
+)
+
+const claimsKey = "claims"
+
+// Authenticate exige um token Bearer válido, de sessão não revogada, e
+// disponibiliza suas claims no contexto
+func Authenticate(keySet *auth.KeySet, revocations auth.RevocationStore) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		token, ok := strings.CutPrefix(ctx.GetHeader("Authorization"), "Bearer ")
+		if !ok || token == "" {
+			response := model.Response{
+				Message: "Essa rota exige autenticação",
+			}
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response)
+			return
+		}
+
+		claims, err := keySet.Verify(token)
+		if err != nil || (claims.SessionID != "" && revocations.IsRevoked(claims.SessionID)) {
+			response := model.Response{
+				Message: "Token inválido ou expirado",
+			}
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response)
+			return
+		}
+
+		ctx.Set(claimsKey, claims)
+		ctx.Next()
+	}
+}
+
+// IdentifyUser disponibiliza as claims quando a requisição traz um token válido,
+// sem bloquear as anônimas; usado nas rotas públicas que mascaram campos
+func IdentifyUser(keySet *auth.KeySet, revocations auth.RevocationStore) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if token, ok := strings.CutPrefix(ctx.GetHeader("Authorization"), "Bearer "); ok && token != "" {
+			claims, err := keySet.Verify(token)
+			if err == nil && (claims.SessionID == "" || !revocations.IsRevoked(claims.SessionID)) {
+				ctx.Set(claimsKey, claims)
+			}
+		}
+		ctx.Next()
+	}
+}
+
+// RequireIdentified exige um usuário autenticado ou uma chave de admin; vem depois de
+// IdentifyAdmin e IdentifyUser
+func RequireIdentified() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if _, ok := Claims(ctx); !ok && !IsAdmin(ctx) {
+			response := model.Response{
+				Message: "Essa rota exige autenticação",
+			}
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, response)
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+// RequireSelf permite acesso apenas ao próprio usuário indicado no parâmetro da rota
+func RequireSelf(param string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		claims, ok := Claims(ctx)
+		if !ok || claims.Subject != ctx.Param(param) {
+			response := model.Response{
+				Message: "Você não tem permissão para acessar este recurso",
+			}
+			ctx.AbortWithStatusJSON(http.StatusForbidden, response)
+			return
+		}
+
+		ctx.Next()
+	}
+}
+
+func Claims(ctx *gin.Context) (*auth.Claims, bool) {
+	claims, ok := ctx.Get(claimsKey)
+	if !ok {
+		return nil, false
+	}
+	return claims.(*auth.Claims), true
+}
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/metrics"
+	"github.com/pytsx/goapi/model"
+)
+
+// Bulkhead limita as requisições simultâneas de um grupo de rotas caras, para
+// que não consumam os recursos das demais. Quem excede o limite espera até
+// queueTimeout por uma vaga e depois recebe 503.
+type Bulkhead struct {
+	name         string
+	slots        chan struct{}
+	queueTimeout time.Duration
+}
+
+func NewBulkhead(name string, limit int, queueTimeout time.Duration) *Bulkhead {
+	return &Bulkhead{
+		name:         name,
+		slots:        make(chan struct{}, limit),
+		queueTimeout: queueTimeout,
+	}
+}
+
+// Handler aplica o limite às requisições para as quais match retorna true (todas, se nil)
+func (b *Bulkhead) Handler(match func(ctx *gin.Context) bool) gin.HandlerFunc {
+	inFlight := metrics.BulkheadInFlight.WithLabelValues(b.name)
+	rejected := metrics.BulkheadRejected.WithLabelValues(b.name)
+
+	return func(ctx *gin.Context) {
+		if match != nil && !match(ctx) {
+			ctx.Next()
+			return
+		}
+
+		if !b.acquire(ctx) {
+			rejected.Inc()
+			response := model.Response{
+				Message: "Muitas requisições simultâneas para este recurso, tente novamente em instantes",
+			}
+			ctx.Header("Retry-After", strconv.Itoa(max(int(b.queueTimeout.Seconds()), 1)))
+			ctx.AbortWithStatusJSON(http.StatusServiceUnavailable, response)
+			return
+		}
+
+		inFlight.Inc()
+		defer func() {
+			inFlight.Dec()
+			<-b.slots
+		}()
+
+		ctx.Next()
+	}
+}
+
+func (b *Bulkhead) acquire(ctx *gin.Context) bool {
+	select {
+	case b.slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	timer := time.NewTimer(b.queueTimeout)
+	defer timer.Stop()
+
+	select {
+	case b.slots <- struct{}{}:
+		return true
+	case <-timer.C:
+		return false
+	case <-ctx.Request.Context().Done():
+		return false
+	}
+}
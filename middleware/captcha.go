@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/captcha"
+	"github.com/pytsx/goapi/model"
+)
+
+const CaptchaHeader = "X-Captcha-Token"
+
+// Captcha exige um token de CAPTCHA válido, exceto para chamadas com uma chave de API confiável
+func Captcha(verifier captcha.Verifier, trustedKeys []string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if apiKey := ctx.GetHeader(APIKeyHeader); apiKey != "" && validAPIKey(trustedKeys, apiKey) {
+			ctx.Next()
+			return
+		}
+
+		token := ctx.GetHeader(CaptchaHeader)
+		if token == "" {
+			response := model.Response{
+				Message: "Essa rota exige a verificação de CAPTCHA",
+			}
+			ctx.AbortWithStatusJSON(http.StatusBadRequest, response)
+			return
+		}
+
+		ok, err := verifier.Verify(ctx.Request.Context(), token, ctx.ClientIP())
+		if err != nil {
+			response := model.Response{
+				Message: "Não foi possível verificar o CAPTCHA, tente novamente",
+			}
+			ctx.AbortWithStatusJSON(http.StatusServiceUnavailable, response)
+			return
+		}
+
+		if !ok {
+			response := model.Response{
+				Message: "CAPTCHA inválido",
+			}
+			ctx.AbortWithStatusJSON(http.StatusForbidden, response)
+			return
+		}
+
+		ctx.Next()
+	}
+}
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/chaos"
+	"github.com/pytsx/goapi/model"
+)
+
+// Chaos injeta latência e erros 503 em uma fração das requisições para testes de resiliência
+func Chaos(injector *chaos.Injector) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if err := injector.Delay(ctx.Request.Context()); err != nil {
+			ctx.Abort()
+			return
+		}
+
+		if injector.HTTPError() {
+			ctx.Header("X-Chaos-Injected", "error")
+			response := model.Response{
+				Message: "Falha injetada pelo modo de caos",
+			}
+			ctx.AbortWithStatusJSON(http.StatusServiceUnavailable, response)
+			return
+		}
+
+		ctx.Next()
+	}
+}
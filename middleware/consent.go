@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/model"
+)
+
+type ConsentChecker interface {
+	PendingConsents(userID int) ([]model.Consent, error)
+}
+
+// RequireConsents bloqueia o usuário autenticado até que aceite as versões vigentes das políticas.
+// Deve ser usado depois de Authenticate ou IdentifyUser; requisições sem token passam.
+func RequireConsents(checker ConsentChecker) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		claims, ok := Claims(ctx)
+		if !ok {
+			ctx.Next()
+			return
+		}
+
+		userID, err := strconv.Atoi(claims.Subject)
+		if err != nil {
+			ctx.Next()
+			return
+		}
+
+		pending, err := checker.PendingConsents(userID)
+		if err != nil {
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if len(pending) > 0 {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"message":          "É necessário aceitar as versões mais recentes dos termos para continuar",
+				"pending_consents": pending,
+			})
+			return
+		}
+
+		ctx.Next()
+	}
+}
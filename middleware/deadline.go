@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const dbContextKey = "db_context"
+
+// DBDeadline define o prazo das consultas da requisição conforme a classe da
+// rota: leituras (GET/HEAD) ou escritas. Repositórios vinculados a DBContext
+// cancelam a consulta no Postgres quando o prazo expira. Zero desativa a classe.
+func DBDeadline(reads, writes time.Duration) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		timeout := writes
+		if ctx.Request.Method == http.MethodGet || ctx.Request.Method == http.MethodHead {
+			timeout = reads
+		}
+		if timeout <= 0 {
+			ctx.Next()
+			return
+		}
+
+		deadline, cancel := context.WithTimeout(ctx.Request.Context(), timeout)
+		defer cancel()
+
+		ctx.Set(dbContextKey, deadline)
+		ctx.Next()
+	}
+}
+
+// DBContext devolve o contexto com o prazo das consultas desta requisição
+func DBContext(ctx *gin.Context) context.Context {
+	if deadline, ok := ctx.Get(dbContextKey); ok {
+		return deadline.(context.Context)
+	}
+	return ctx.Request.Context()
+}
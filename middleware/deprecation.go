@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/metrics"
+)
+
+// Deprecation descreve a obsolescência de uma rota ou de um parâmetro de query
+type Deprecation struct {
+	// data a partir da qual o uso é desaconselhado
+	Since time.Time
+	// data prevista para a remoção; zero omite o header Sunset
+	Sunset time.Time
+	// documentação da alternativa, enviada em Link rel="deprecation"
+	Link string
+}
+
+// Deprecated marca toda a rota como obsoleta (RFC 9745 e RFC 8594)
+func Deprecated(deprecation Deprecation) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		announce(ctx, deprecation, "")
+		ctx.Next()
+	}
+}
+
+// DeprecatedParam marca apenas o parâmetro de query, sinalizando somente as requisições que o utilizam
+func DeprecatedParam(param string, deprecation Deprecation) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if _, ok := ctx.GetQuery(param); ok {
+			announce(ctx, deprecation, param)
+		}
+		ctx.Next()
+	}
+}
+
+func announce(ctx *gin.Context, deprecation Deprecation, param string) {
+	ctx.Header("Deprecation", "@"+strconv.FormatInt(deprecation.Since.Unix(), 10))
+	if !deprecation.Sunset.IsZero() {
+		ctx.Header("Sunset", deprecation.Sunset.UTC().Format(http.TimeFormat))
+	}
+	if deprecation.Link != "" {
+		ctx.Writer.Header().Add("Link", "<"+deprecation.Link+`>; rel="deprecation"`)
+	}
+
+	metrics.DeprecatedRequests.WithLabelValues(ctx.Request.Method, ctx.FullPath(), param).Inc()
+}
@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/geoip"
+	"github.com/pytsx/goapi/model"
+)
+
+// GeoIP anexa país/cidade do cliente ao contexto da requisição e bloqueia
+// os países listados (códigos ISO, ex.: "KP")
+func GeoIP(resolver geoip.Resolver, blockedCountries []string) gin.HandlerFunc {
+	blocked := map[string]bool{}
+	for _, country := range blockedCountries {
+		blocked[strings.ToUpper(country)] = true
+	}
+
+	return func(ctx *gin.Context) {
+		ip := net.ParseIP(ctx.ClientIP())
+		if ip == nil {
+			ctx.Next()
+			return
+		}
+
+		location, err := resolver.Lookup(ip)
+		if err != nil {
+			ctx.Next()
+			return
+		}
+
+		ctx.Request = ctx.Request.WithContext(geoip.WithLocation(ctx.Request.Context(), location))
+
+		if blocked[location.Country] {
+			response := model.Response{
+				Message: "Serviço indisponível na sua região",
+			}
+			ctx.AbortWithStatusJSON(http.StatusUnavailableForLegalReasons, response)
+			return
+		}
+
+		ctx.Next()
+	}
+}
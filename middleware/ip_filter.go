@@ -0,0 +1,204 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/netip"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/model"
+)
+
+// CIDRRule define as faixas liberadas e bloqueadas. Uma lista de allow vazia libera todos
+type CIDRRule struct {
+	Allow []string `json:"allow"`
+	Deny  []string `json:"deny"`
+}
+
+// IPFilterRules é o formato do arquivo de regras, ex.:
+//
+//	{"global": {"deny": ["203.0.113.0/24"]}, "groups": {"admin": {"allow": ["10.8.0.0/16"]}}}
+type IPFilterRules struct {
+	Global CIDRRule            `json:"global"`
+	Groups map[string]CIDRRule `json:"groups"`
+}
+
+type prefixRule struct {
+	allow []netip.Prefix
+	deny  []netip.Prefix
+}
+
+type compiledRules struct {
+	global prefixRule
+	groups map[string]prefixRule
+}
+
+// IPFilter aplica as regras de CIDR e as recarrega quando o arquivo muda
+type IPFilter struct {
+	file    string
+	modTime time.Time
+	rules   atomic.Pointer[compiledRules]
+}
+
+func NewIPFilter(file string) (*IPFilter, error) {
+	filter := &IPFilter{file: file}
+	filter.rules.Store(&compiledRules{})
+
+	if file == "" {
+		return filter, nil
+	}
+
+	if err := filter.Reload(); err != nil {
+		return nil, err
+	}
+
+	return filter, nil
+}
+
+// Reload lê o arquivo de regras; em caso de erro as regras atuais são mantidas
+func (f *IPFilter) Reload() error {
+	info, err := os.Stat(f.file)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(f.file)
+	if err != nil {
+		return err
+	}
+
+	var rules IPFilterRules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return err
+	}
+
+	compiled, err := compileRules(rules)
+	if err != nil {
+		return err
+	}
+
+	f.rules.Store(compiled)
+	f.modTime = info.ModTime()
+	return nil
+}
+
+// Watch verifica periodicamente o arquivo e recarrega as regras quando ele é alterado
+func (f *IPFilter) Watch(interval time.Duration) {
+	if f.file == "" {
+		return
+	}
+
+	go func() {
+		for range time.Tick(interval) {
+			info, err := os.Stat(f.file)
+			if err != nil || !info.ModTime().After(f.modTime) {
+				continue
+			}
+
+			if err := f.Reload(); err != nil {
+				log.Printf("ip filter: mantendo regras anteriores, falha ao recarregar: %v", err)
+				continue
+			}
+			log.Printf("ip filter: regras recarregadas de %s", f.file)
+		}
+	}()
+}
+
+// Global aplica as regras globais a todas as rotas
+func (f *IPFilter) Global() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		f.check(ctx, f.rules.Load().global)
+	}
+}
+
+// Group aplica as regras do grupo informado (ex.: "admin")
+func (f *IPFilter) Group(name string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		f.check(ctx, f.rules.Load().groups[name])
+	}
+}
+
+func (f *IPFilter) check(ctx *gin.Context, rule prefixRule) {
+	addr, err := netip.ParseAddr(ctx.ClientIP())
+	if err != nil || !rule.permits(addr.Unmap()) {
+		response := model.Response{
+			Message: "Acesso não permitido a partir deste endereço",
+		}
+		ctx.AbortWithStatusJSON(http.StatusForbidden, response)
+		return
+	}
+
+	ctx.Next()
+}
+
+func (r prefixRule) permits(addr netip.Addr) bool {
+	for _, prefix := range r.deny {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+
+	if len(r.allow) == 0 {
+		return true
+	}
+
+	for _, prefix := range r.allow {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+func compileRules(rules IPFilterRules) (*compiledRules, error) {
+	global, err := compileRule(rules.Global)
+	if err != nil {
+		return nil, err
+	}
+
+	compiled := &compiledRules{
+		global: global,
+		groups: map[string]prefixRule{},
+	}
+
+	for name, rule := range rules.Groups {
+		group, err := compileRule(rule)
+		if err != nil {
+			return nil, fmt.Errorf("grupo %s: %w", name, err)
+		}
+		compiled.groups[name] = group
+	}
+
+	return compiled, nil
+}
+
+func compileRule(rule CIDRRule) (prefixRule, error) {
+	allow, err := parsePrefixes(rule.Allow)
+	if err != nil {
+		return prefixRule{}, err
+	}
+
+	deny, err := parsePrefixes(rule.Deny)
+	if err != nil {
+		return prefixRule{}, err
+	}
+
+	return prefixRule{allow: allow, deny: deny}, nil
+}
+
+func parsePrefixes(cidrs []string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, cidr := range cidrs {
+		prefix, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return nil, err
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
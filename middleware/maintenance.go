@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/model"
+)
+
+// MaintenanceMode recusa as requisições enquanto ligado, exceto as que trazem
+// uma chave administrativa e as dos caminhos isentos. O estado vem da configuração de
+// execução, que cada instância acompanha (ConfigUsecase.Watch).
+type MaintenanceMode struct {
+	enabled   atomic.Bool
+	adminKeys []string
+	exempt    map[string]bool
+}
+
+// exemptPaths costumam ser as sondas de saúde: se falhassem, o balanceador tiraria
+// a instância do ar e ninguém conseguiria desligar a manutenção por ele
+func NewMaintenanceMode(adminKeys []string, exemptPaths ...string) *MaintenanceMode {
+	exempt := map[string]bool{}
+	for _, path := range exemptPaths {
+		exempt[path] = true
+	}
+
+	return &MaintenanceMode{
+		adminKeys: adminKeys,
+		exempt:    exempt,
+	}
+}
+
+func (m *MaintenanceMode) Set(enabled bool) {
+	m.enabled.Store(enabled)
+}
+
+func (m *MaintenanceMode) Enabled() bool {
+	return m.enabled.Load()
+}
+
+func (m *MaintenanceMode) Handler() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if !m.Enabled() || m.exempt[ctx.Request.URL.Path] || validAPIKey(m.adminKeys, ctx.GetHeader(APIKeyHeader)) {
+			ctx.Next()
+			return
+		}
+
+		response := model.Response{
+			Message: "A API está em manutenção, tente novamente em alguns minutos",
+		}
+		ctx.Header("Retry-After", "120")
+		ctx.AbortWithStatusJSON(http.StatusServiceUnavailable, response)
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/metrics"
+	"github.com/pytsx/goapi/tracing"
+)
+
+// RequestMetrics mede a latência de cada requisição. Com exemplars, as requisições
+// amostradas por um trace (header traceparent do W3C Trace Context) levam o trace id
+// para o histograma, permitindo ir de um pico de latência direto ao trace
+func RequestMetrics(exemplars bool) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		start := time.Now()
+		ctx.Next()
+
+		route := ctx.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		observer := metrics.RequestDuration.WithLabelValues(ctx.Request.Method, route, strconv.Itoa(ctx.Writer.Status()))
+
+		var traceID string
+		if exemplars {
+			traceID = tracing.SampledTraceID(ctx.GetHeader("traceparent"))
+		}
+		metrics.ObserveWithTrace(observer, time.Since(start).Seconds(), traceID)
+	}
+}
+
+// TraceContext guarda o traceparent recebido no contexto da requisição, para as chamadas
+// de saída (httpclient) continuarem o mesmo trace
+func TraceContext() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		if parent, ok := tracing.Parse(ctx.GetHeader("traceparent")); ok {
+			ctx.Request = ctx.Request.WithContext(tracing.NewContext(ctx.Request.Context(), parent))
+		}
+		ctx.Next()
+	}
+}
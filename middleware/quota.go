@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/quota"
+)
+
+// DailyQuota limita as requisições por dia de cada chave de API listada em keys.
+// É uma cota branda: se o contador estiver indisponível, a requisição segue.
+func DailyQuota(counter quota.Counter, keys []string, limits *quota.Limits) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		limit := limits.DailyRequests()
+		provided := ctx.GetHeader(APIKeyHeader)
+		if limit <= 0 || provided == "" || !validAPIKey(keys, provided) {
+			ctx.Next()
+			return
+		}
+
+		used, err := counter.Increment(ctx.Request.Context(), quota.KeyID(provided), quota.Today())
+		if err != nil {
+			log.Printf("cota: contador indisponível: %v", err)
+			ctx.Next()
+			return
+		}
+
+		reset := quota.NextReset()
+		remaining := max(int64(limit)-used, 0)
+		ctx.Header("X-Quota-Limit", strconv.Itoa(limit))
+		ctx.Header("X-Quota-Remaining", strconv.FormatInt(remaining, 10))
+		ctx.Header("X-Quota-Reset", reset.Format(time.RFC3339))
+
+		if used > int64(limit) {
+			response := model.Response{
+				Message: "Cota diária de " + strconv.Itoa(limit) + " requisições esgotada para esta chave; renova em " +
+					reset.Format(time.RFC3339),
+			}
+			ctx.Header("Retry-After", strconv.Itoa(int(time.Until(reset).Seconds())+1))
+			ctx.AbortWithStatusJSON(http.StatusTooManyRequests, response)
+			return
+		}
+
+		ctx.Next()
+	}
+}
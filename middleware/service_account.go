@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/auth"
+	"github.com/pytsx/goapi/model"
+)
+
+const serviceAccountKey = "service_account"
+
+// ServiceAccounts mapeia o certificado de cliente das conexões mTLS para uma
+// conta de serviço. Conexões sem certificado (listener público) seguem normalmente.
+func ServiceAccounts(accounts map[string]string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tlsState := ctx.Request.TLS
+		if tlsState == nil || len(tlsState.PeerCertificates) == 0 {
+			ctx.Next()
+			return
+		}
+
+		for _, identity := range auth.CertificateIdentities(tlsState.PeerCertificates[0]) {
+			if account, ok := accounts[identity]; ok {
+				ctx.Set(serviceAccountKey, account)
+				ctx.Next()
+				return
+			}
+		}
+
+		response := model.Response{
+			Message: "Certificado não associado a nenhuma conta de serviço",
+		}
+		ctx.AbortWithStatusJSON(http.StatusForbidden, response)
+	}
+}
+
+// ServiceAccount retorna a conta de serviço autenticada via mTLS, se houver
+func ServiceAccount(ctx *gin.Context) (string, bool) {
+	account, ok := ctx.Get(serviceAccountKey)
+	if !ok {
+		return "", false
+	}
+	return account.(string), true
+}
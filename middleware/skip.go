@@ -0,0 +1,8 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+// Skip é usado no lugar de middlewares opcionais desativados na configuração
+func Skip(ctx *gin.Context) {
+	ctx.Next()
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Actor identifica quem originou a operação, para fins de auditoria. Nas respostas
+// mascaradas, o usuário só vê o próprio id de ator; IP e país apenas os privilegiados
+type Actor struct {
+	ID      string `json:"actor" mask:"owner"`
+	IP      string `json:"ip" mask:"privileged"`
+	Country string `json:"country" mask:"privileged"`
+}
+
+// OwnerID é o usuário por trás de atores "user:<id>"; zero para chaves de API e sistema
+func (a Actor) OwnerID() int {
+	id, ok := strings.CutPrefix(a.ID, "user:")
+	if !ok {
+		return 0
+	}
+	owner, _ := strconv.Atoi(id)
+	return owner
+}
+
+type AuditEntry struct {
+	ID        int64          `json:"audit_id"`
+	Actor     Actor          `json:"actor"`
+	Action    string         `json:"action"`
+	Entity    string         `json:"entity"`
+	EntityID  string         `json:"entity_id"`
+	Metadata  map[string]any `json:"metadata" mask:"privileged"`
+	CreatedAt time.Time      `json:"created_at"`
+}
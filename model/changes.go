@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+const (
+	ChangeCreated = "created"
+	ChangeUpdated = "updated"
+	ChangeDeleted = "deleted"
+)
+
+// UserChange é uma alteração de usuário registrada depois do cursor informado
+type UserChange struct {
+	UserID int       `json:"user_id"`
+	Change string    `json:"change"`
+	At     time.Time `json:"at"`
+}
+
+// ChangeCursor marca a última alteração entregue; o id desempata alterações no mesmo instante
+type ChangeCursor struct {
+	At     time.Time
+	UserID int
+}
+
+type UserChanges struct {
+	Changes []UserChange `json:"changes"`
+	// passado em ?since= na próxima chamada
+	NextCursor string `json:"next_cursor"`
+	// há mais alterações além desta página; chame de novo imediatamente
+	HasMore bool `json:"has_more"`
+}
@@ -0,0 +1,9 @@
+package model
+
+import "time"
+
+type Consent struct {
+	Policy     string     `json:"policy" binding:"required"`
+	Version    string     `json:"version" binding:"required"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+}
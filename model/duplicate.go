@@ -0,0 +1,19 @@
+package model
+
+const (
+	DuplicateByEmail = "email"
+	DuplicateByName  = "name"
+)
+
+type DuplicateCheck struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// DuplicateMatch é um usuário existente parecido com os dados informados.
+// Score vai de 0 a 1; coincidências de email valem sempre 1.
+type DuplicateMatch struct {
+	User   User    `json:"user"`
+	Reason string  `json:"reason"`
+	Score  float64 `json:"score"`
+}
@@ -0,0 +1,14 @@
+package model
+
+const (
+	EmailBounce    = "bounce"
+	EmailComplaint = "complaint"
+)
+
+// EmailEvent é um bounce permanente ou reclamação de spam reportado pelo provedor de email
+type EmailEvent struct {
+	Email    string `json:"email"`
+	Type     string `json:"type"`
+	Provider string `json:"provider"`
+	Detail   string `json:"detail"`
+}
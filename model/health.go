@@ -0,0 +1,26 @@
+package model
+
+// HealthReport é a resposta do /readyz
+type HealthReport struct {
+	// "ok" quando todas as verificações passaram, "degraded" quando só dependências
+	// opcionais falharam (responde 200) e "fail" caso contrário
+	Status string        `json:"status"`
+	Checks []HealthCheck `json:"checks"`
+	// dependências opcionais; desativadas ou fora do ar, não tiram a instância do balanceador
+	Capabilities []HealthCapability `json:"capabilities"`
+}
+
+type HealthCheck struct {
+	Name      string  `json:"name"`
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latency_ms"`
+}
+
+type HealthCapability struct {
+	Name string `json:"name"`
+	// "enabled", "disabled" ou "degraded" (configurada, mas fora do ar)
+	Status   string   `json:"status"`
+	Features []string `json:"features"`
+	// o que substitui os recursos enquanto a dependência está desativada
+	Fallback string `json:"fallback,omitempty"`
+}
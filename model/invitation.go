@@ -0,0 +1,45 @@
+package model
+
+const (
+	InvitationActive    = "active"
+	InvitationExpired   = "expired"
+	InvitationExhausted = "exhausted"
+	InvitationRevoked   = "revoked"
+)
+
+// Invitation é um código de convite para o cadastro, com usos e validade limitados
+type Invitation struct {
+	ID int64 `json:"invitation_id"`
+	// o código em si só é devolvido na criação; depois, apenas os primeiros caracteres
+	Code     string     `json:"code,omitempty"`
+	Link     NullString `json:"link,omitempty"`
+	CodeHint string     `json:"code_hint"`
+	Note     string     `json:"note"`
+	MaxUses  int        `json:"max_uses"`
+	Uses     int        `json:"uses"`
+	// calculado a partir da validade, dos usos e da revogação
+	Status    string `json:"status"`
+	ExpiresAt Time   `json:"expires_at"`
+	RevokedAt Time   `json:"revoked_at"`
+	CreatedBy string `json:"created_by"`
+	CreatedAt Time   `json:"created_at"`
+}
+
+// InvitationRequest cria um convite; sem expires_at, o convite não expira
+type InvitationRequest struct {
+	MaxUses   int    `json:"max_uses"`
+	ExpiresAt Time   `json:"expires_at"`
+	Note      string `json:"note"`
+}
+
+// InvitationUse registra um cadastro feito com o convite. UserID é zero quando a conta já foi removida
+type InvitationUse struct {
+	UserID int  `json:"user_id"`
+	UsedAt Time `json:"used_at"`
+}
+
+// InvitationReport é a resposta de GET /admin/invitations/:id
+type InvitationReport struct {
+	Invitation
+	UsedBy []InvitationUse `json:"used_by"`
+}
@@ -0,0 +1,54 @@
+package model
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	JobPending = "pending"
+	JobRunning = "running"
+	JobDone    = "done"
+	// esgotou as tentativas: fica na fila de mensagens mortas até ser reprocessado
+	JobDead = "dead"
+
+	JobWelcomeEmail = "welcome_email"
+)
+
+type Job struct {
+	ID          int64           `json:"job_id"`
+	Queue       string          `json:"queue"`
+	Kind        string          `json:"kind"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	LastError   string          `json:"last_error"`
+	RunAt       time.Time       `json:"run_at"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+type WelcomeEmailPayload struct {
+	UserID int `json:"user_id"`
+	// idioma detectado no cadastro, usado se o usuário não tiver um salvo
+	Locale string `json:"locale,omitempty"`
+}
+
+// JobQueueStats resume uma fila para o painel administrativo
+type JobQueueStats struct {
+	Queue   string `json:"queue"`
+	Pending int    `json:"pending"`
+	// pendentes que já falharam ao menos uma vez e aguardam nova tentativa
+	Retrying int        `json:"retrying"`
+	Running  int        `json:"running"`
+	Done     int        `json:"done"`
+	Dead     int        `json:"dead"`
+	OldestAt *time.Time `json:"oldest_pending_at"`
+}
+
+type JobFilter struct {
+	Queue  string
+	Kind   string
+	Status string
+}
@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+type Credentials struct {
+	Email    string `json:"email" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type Token struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+	// formato <session_id>.<segredo>; só o hash do segredo é persistido
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+type Login struct {
+	ID      int    `json:"login_id"`
+	UserID  int    `json:"user_id"`
+	Device  string `json:"device"`
+	IP      string `json:"ip"`
+	Country string `json:"country"`
+	City    string `json:"city"`
+	// país/cidade quando há GeoIP, senão o próprio IP; usado para comparar com o histórico
+	Location  string    `json:"location"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type SuspiciousLogin struct {
+	Login       Login `json:"login"`
+	NewDevice   bool  `json:"new_device"`
+	NewLocation bool  `json:"new_location"`
+}
+
+// PasswordChange exige a senha atual, dispensada apenas quando a sessão acabou de ser aberta com login
+type PasswordChange struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password" binding:"required"`
+}
+
+// PasswordChanged é o payload do evento user.password_changed
+type PasswordChanged struct {
+	UserID          int   `json:"user_id"`
+	Actor           Actor `json:"actor"`
+	RevokedSessions int   `json:"revoked_sessions"`
+}
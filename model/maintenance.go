@@ -0,0 +1,5 @@
+package model
+
+type MaintenanceStatus struct {
+	Enabled bool `json:"enabled"`
+}
@@ -0,0 +1,43 @@
+package model
+
+const (
+	ModerationApproved ModerationStatus = "approved"
+	ModerationFlagged  ModerationStatus = "flagged"
+	ModerationRejected ModerationStatus = "rejected"
+
+	AvatarNone        = "none"
+	AvatarApproved    = "approved"
+	AvatarQuarantined = "quarantined"
+)
+
+// ModerationCase é um perfil sinalizado aguardando (ou já com) revisão de um admin
+type ModerationCase struct {
+	UserID      int              `json:"user_id"`
+	Name        string           `json:"name"`
+	Email       string           `json:"email"`
+	Status      ModerationStatus `json:"moderation_status"`
+	StatusLabel string           `json:"moderation_status_label"`
+	Reason      NullString       `json:"moderation_reason"`
+	ModeratedAt Time             `json:"moderated_at"`
+}
+
+type ModerationReview struct {
+	Action ModerationAction `json:"action" binding:"required"`
+}
+
+// UserModeration reúne a situação de moderação do perfil, visível apenas para admins
+type UserModeration struct {
+	NameStatus          ModerationStatus `json:"name_status"`
+	NameStatusLabel     string           `json:"name_status_label"`
+	NameReason          NullString       `json:"name_reason"`
+	ModeratedAt         Time             `json:"moderated_at"`
+	AvatarStatus        string           `json:"avatar_status"`
+	AvatarStatusLabel   string           `json:"avatar_status_label"`
+	AvatarReason        NullString       `json:"avatar_reason"`
+	AvatarQuarantineKey NullString       `json:"avatar_quarantine_key"`
+}
+
+type AdminUser struct {
+	User
+	Moderation UserModeration `json:"moderation"`
+}
@@ -0,0 +1,14 @@
+package model
+
+type Preferences struct {
+	// envia o email "foi você?" ao detectar login de novo dispositivo ou local
+	LoginAlerts bool `json:"login_alerts"`
+	// idioma dos emails (pt-BR ou en). Vazio usa o idioma detectado na requisição que originou o envio
+	Locale string `json:"locale"`
+}
+
+// PreferencesUpdate é o corpo do PUT /preferences: campos ausentes (ou null) mantêm o valor atual
+type PreferencesUpdate struct {
+	LoginAlerts *bool   `json:"login_alerts"`
+	Locale      *string `json:"locale"`
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// UserRelated reúne, numa única resposta, o que os painéis mostram ao lado de um usuário
+type UserRelated struct {
+	UserID           int        `json:"user_id"`
+	ActiveSessions   int        `json:"active_sessions"`
+	AcceptedConsents int        `json:"accepted_consents"`
+	LastLoginAt      *time.Time `json:"last_login_at"`
+	IsOnline         bool       `json:"is_online"`
+	// ações feitas pelo usuário ou sobre ele, das mais recentes para as mais antigas
+	RecentActivity []AuditEntry `json:"recent_activity"`
+}
+
+func (r UserRelated) OwnerID() int {
+	return r.UserID
+}
@@ -2,4 +2,6 @@ package model
 
 type Response struct {
 	Message string `json:"message"`
+	// detalhes por campo, usados nos erros de validação
+	Details map[string]string `json:"details,omitempty"`
 }
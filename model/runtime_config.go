@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// RuntimeConfigSchema é a versão do formato do documento exportado. Importações
+// com outra versão são recusadas.
+const RuntimeConfigSchema = 1
+
+// RuntimeConfig reúne as configurações ajustáveis sem reiniciar a API. A
+// importação substitui o documento inteiro: exporte, edite e importe.
+type RuntimeConfig struct {
+	SchemaVersion int `json:"schema_version"`
+	// revisão gravada; na importação é só a origem do documento (a conferência usa If-Match)
+	Revision   int64         `json:"revision"`
+	ExportedAt *time.Time    `json:"exported_at,omitempty"`
+	Flags      RuntimeFlags  `json:"flags"`
+	Quotas     RuntimeQuotas `json:"quotas"`
+}
+
+type RuntimeFlags struct {
+	Maintenance bool `json:"maintenance"`
+	// cadastro público apenas com convite
+	InviteOnly bool `json:"invite_only"`
+}
+
+// RuntimeQuotas usa zero para desativar o limite
+type RuntimeQuotas struct {
+	MaxUsers      int `json:"max_users"`
+	DailyRequests int `json:"daily_requests"`
+}
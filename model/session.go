@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+type Session struct {
+	ID               string    `json:"session_id"`
+	UserID           int       `json:"user_id"`
+	Device           string    `json:"device"`
+	IP               string    `json:"ip"`
+	CreatedAt        time.Time `json:"created_at"`
+	LastSeenAt       time.Time `json:"last_seen_at"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshTokenHash string    `json:"-"`
+	// indica a sessão do token usado na requisição
+	Current bool `json:"current"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
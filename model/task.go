@@ -0,0 +1,37 @@
+package model
+
+import "encoding/json"
+
+const (
+	TaskPending   TaskStatus = "pending"
+	TaskRunning   TaskStatus = "running"
+	TaskSucceeded TaskStatus = "succeeded"
+	TaskFailed    TaskStatus = "failed"
+
+	TaskReindex       = "reindex"
+	TaskUserExport    = "user_export"
+	TaskUserImport    = "user_import"
+	TaskUserAnonymize = "user_anonymize"
+)
+
+// Task acompanha uma operação longa executada pelo worker de jobs
+type Task struct {
+	ID       int64      `json:"task_id"`
+	Kind     string     `json:"kind"`
+	Status   TaskStatus `json:"status"`
+	Progress int        `json:"progress"`
+	// onde baixar o resultado, quando a operação produz um arquivo
+	ResultURL  NullString `json:"result_url"`
+	Errors     []string   `json:"errors"`
+	Actor      string     `json:"actor"`
+	CreatedAt  Time       `json:"created_at"`
+	UpdatedAt  Time       `json:"updated_at"`
+	FinishedAt Time       `json:"finished_at"`
+}
+
+// TaskPayload é o payload dos jobs que executam tasks
+type TaskPayload struct {
+	TaskID int64           `json:"task_id"`
+	Actor  Actor           `json:"actor"`
+	Input  json.RawMessage `json:"input,omitempty"`
+}
@@ -0,0 +1,182 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Tipos compartilhados pelos recursos da API. Na decodificação, valores fora do esperado
+// viram *json.UnmarshalTypeError sem o nome do campo, que o encoding/json não completa nos
+// erros de UnmarshalJSON (o controller o encontra pela tag json); na serialização, ausência
+// é sempre null
+
+// NullString é um texto opcional. As colunas correspondentes são NOT NULL com padrão vazio,
+// então string vazia e null são a mesma coisa: no banco grava "", no JSON aparece null
+type NullString string
+
+func (s NullString) MarshalJSON() ([]byte, error) {
+	if s == "" {
+		return []byte("null"), nil
+	}
+	return json.Marshal(string(s))
+}
+
+func (s *NullString) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*s = ""
+		return nil
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return typeError(data, s, "esperado texto ou null")
+	}
+	*s = NullString(value)
+	return nil
+}
+
+func (s *NullString) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		*s = ""
+	case string:
+		*s = NullString(src)
+	case []byte:
+		*s = NullString(src)
+	default:
+		return fmt.Errorf("NullString: tipo %T não suportado", src)
+	}
+	return nil
+}
+
+func (s NullString) Value() (driver.Value, error) {
+	return string(s), nil
+}
+
+// Time é um instante serializado sempre em UTC no formato RFC 3339. O valor zero
+// corresponde a null, tanto no JSON quanto no banco
+type Time struct {
+	time.Time
+}
+
+func NewTime(t time.Time) Time {
+	return Time{Time: t}
+}
+
+func (t Time) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(`"` + t.UTC().Format(time.RFC3339Nano) + `"`), nil
+}
+
+func (t *Time) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*t = Time{}
+		return nil
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return typeError(data, t, "esperada data no formato RFC 3339")
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return typeError(data, t, "esperada data no formato RFC 3339")
+	}
+	*t = Time{Time: parsed}
+	return nil
+}
+
+func (t *Time) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		*t = Time{}
+	case time.Time:
+		*t = Time{Time: src}
+	default:
+		return fmt.Errorf("Time: tipo %T não suportado", src)
+	}
+	return nil
+}
+
+func (t Time) Value() (driver.Value, error) {
+	if t.IsZero() {
+		return nil, nil
+	}
+	return t.Time, nil
+}
+
+// ModerationStatus é a situação do nome do usuário após a moderação
+type ModerationStatus string
+
+var moderationStatuses = []ModerationStatus{ModerationApproved, ModerationFlagged, ModerationRejected}
+
+func (s *ModerationStatus) UnmarshalJSON(data []byte) error {
+	return decodeEnum(data, s, moderationStatuses)
+}
+
+// ModerationAction é a decisão do admin ao revisar um perfil sinalizado
+type ModerationAction string
+
+const (
+	ModerationApprove ModerationAction = "approve"
+	ModerationReject  ModerationAction = "reject"
+)
+
+var moderationActions = []ModerationAction{ModerationApprove, ModerationReject}
+
+func (a *ModerationAction) UnmarshalJSON(data []byte) error {
+	return decodeEnum(data, a, moderationActions)
+}
+
+// TaskStatus é a etapa em que uma task se encontra
+type TaskStatus string
+
+var taskStatuses = []TaskStatus{TaskPending, TaskRunning, TaskSucceeded, TaskFailed}
+
+func (s *TaskStatus) UnmarshalJSON(data []byte) error {
+	return decodeEnum(data, s, taskStatuses)
+}
+
+// decodeEnum aceita apenas um dos valores permitidos; null mantém o valor atual
+func decodeEnum[T ~string](data []byte, target *T, allowed []T) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	names := make([]string, len(allowed))
+	for i, value := range allowed {
+		names[i] = string(value)
+	}
+	expected := "esperado um de: " + strings.Join(names, ", ")
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return typeError(data, target, expected)
+	}
+	for _, candidate := range allowed {
+		if string(candidate) == value {
+			*target = candidate
+			return nil
+		}
+	}
+	return typeError(data, target, expected)
+}
+
+// typeError descreve o valor recebido e o que era esperado; o campo é preenchido pelo
+// encoding/json ao propagar o erro
+func typeError(data []byte, target any, expected string) error {
+	received := string(data)
+	if len(received) > 64 {
+		received = received[:64] + "..."
+	}
+	return &json.UnmarshalTypeError{
+		Value: received + " (" + expected + ")",
+		Type:  reflect.TypeOf(target).Elem(),
+	}
+}
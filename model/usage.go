@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// UsageReport resume o consumo das cotas. Limite zero significa sem limite
+type UsageReport struct {
+	Users   QuotaUsage    `json:"users"`
+	APIKeys []APIKeyUsage `json:"api_keys"`
+	ResetAt time.Time     `json:"reset_at"`
+}
+
+type QuotaUsage struct {
+	Used  int64 `json:"used"`
+	Limit int64 `json:"limit"`
+}
+
+type APIKeyUsage struct {
+	// prefixo do hash da chave; o segredo nunca aparece nos relatórios
+	KeyID         string     `json:"key_id"`
+	RequestsToday QuotaUsage `json:"requests_today"`
+}
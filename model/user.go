@@ -1,8 +1,61 @@
 package model
 
+import "time"
+
 type User struct {
-	ID     int    `json:"user_id"`
-	Name   string `json:"name"`
-	Email  string `json:"email"`
-	ImgURL string `json:"img_url"`
-}
\ No newline at end of file
+	ID     int        `json:"user_id"`
+	Name   string     `json:"name"`
+	Email  string     `json:"email" mask:"owner"`
+	ImgURL NullString `json:"img_url"`
+	// o provedor de email reportou bounce permanente ou reclamação para este endereço
+	EmailUndeliverable bool `json:"email_undeliverable" mask:"owner"`
+	CreatedAt          Time `json:"created_at"`
+	// percentual de 0 a 100 dos dados de perfil preenchidos, recalculado a cada alteração
+	ProfileCompleteness int `json:"profile_completeness"`
+	// calculado a partir dos heartbeats de presença, não é persistido
+	IsOnline bool `json:"is_online"`
+	// senha em texto puro recebida no cadastro; nunca é devolvida nem persistida
+	Password     string `json:"password,omitempty"`
+	PasswordHash string `json:"-"`
+	// idioma do Accept-Language do cadastro; não é persistido
+	Locale string `json:"-"`
+	// código de convite informado no cadastro; nunca é devolvido nem persistido no usuário
+	InviteCode string `json:"invite_code,omitempty"`
+	// resultado da moderação do nome no cadastro
+	ModerationStatus ModerationStatus `json:"-"`
+	ModerationReason string           `json:"-"`
+}
+
+// OwnerID permite que o próprio usuário veja os campos marcados com mask:"owner"
+func (u User) OwnerID() int {
+	return u.ID
+}
+
+// campos aceitos em UserFilter.Sort
+var UserSortFields = []string{"id", "name", "created_at", "profile_completeness"}
+
+// UserFilter restringe e ordena a listagem de usuários; campos vazios não filtram
+type UserFilter struct {
+	// trecho do nome, sem diferenciar maiúsculas
+	Name          string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	// faixa de profile_completeness, inclusiva nas duas pontas
+	MinCompleteness *int
+	MaxCompleteness *int
+	// um dos UserSortFields, com prefixo "-" para ordem decrescente
+	Sort string
+}
+
+// ProfileFacts são os dados do perfil que compõem o profile_completeness
+type ProfileFacts struct {
+	HasAvatar        bool
+	EmailDeliverable bool
+	HasPassword      bool
+	HasLocale        bool
+}
+
+// ProfileUpdated é publicado quando muda algum dado que compõe o profile_completeness
+type ProfileUpdated struct {
+	UserID int `json:"user_id"`
+}
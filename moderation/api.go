@@ -0,0 +1,71 @@
+package moderation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pytsx/goapi/httpclient"
+)
+
+// APIModerator delega a análise a um serviço externo que recebe {"text": "..."}
+// e responde {"verdict": "allow|flag|reject", "reason": "..."}
+type APIModerator struct {
+	endpoint string
+	apiKey   string
+	client   *httpclient.Client
+}
+
+func NewAPIModerator(endpoint, apiKey string, outbound httpclient.Config) APIModerator {
+	return APIModerator{
+		endpoint: endpoint,
+		apiKey:   apiKey,
+		client:   httpclient.New("moderation", outbound.WithTimeout(5*time.Second)),
+	}
+}
+
+func (m APIModerator) Moderate(ctx context.Context, text string) (Result, error) {
+	payload, err := json.Marshal(map[string]string{"text": text})
+	if err != nil {
+		return Result{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return Result{}, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if m.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+m.apiKey)
+	}
+
+	resp, err := m.client.Do(req)
+	if err != nil {
+		return Result{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Result{}, fmt.Errorf("serviço de moderação retornou %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Verdict string `json:"verdict"`
+		Reason  string `json:"reason"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return Result{}, err
+	}
+
+	switch body.Verdict {
+	case "reject":
+		return Result{Verdict: Reject, Reason: body.Reason}, nil
+	case "flag":
+		return Result{Verdict: Flag, Reason: body.Reason}, nil
+	default:
+		return Result{Verdict: Allow}, nil
+	}
+}
@@ -0,0 +1,53 @@
+package moderation
+
+import (
+	"context"
+)
+
+type Verdict int
+
+// em ordem de severidade
+const (
+	Allow Verdict = iota
+	Flag
+	Reject
+)
+
+func (v Verdict) String() string {
+	switch v {
+	case Flag:
+		return "flag"
+	case Reject:
+		return "reject"
+	default:
+		return "allow"
+	}
+}
+
+type Result struct {
+	Verdict Verdict
+	Reason  string
+}
+
+type Moderator interface {
+	Moderate(ctx context.Context, text string) (Result, error)
+}
+
+// Chain consulta todos os moderadores e fica com o veredito mais severo
+type Chain []Moderator
+
+func (c Chain) Moderate(ctx context.Context, text string) (Result, error) {
+	result := Result{Verdict: Allow}
+
+	for _, moderator := range c {
+		r, err := moderator.Moderate(ctx, text)
+		if err != nil {
+			return Result{}, err
+		}
+		if r.Verdict > result.Verdict {
+			result = r
+		}
+	}
+
+	return result, nil
+}
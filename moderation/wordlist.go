@@ -0,0 +1,49 @@
+package moderation
+
+import (
+	"context"
+	"strings"
+	"unicode"
+)
+
+// WordlistModerator compara palavras inteiras (sem diferenciar maiúsculas) com listas locais
+type WordlistModerator struct {
+	rejected map[string]bool
+	flagged  map[string]bool
+}
+
+func NewWordlistModerator(rejected, flagged []string) WordlistModerator {
+	return WordlistModerator{
+		rejected: toSet(rejected),
+		flagged:  toSet(flagged),
+	}
+}
+
+func (m WordlistModerator) Moderate(ctx context.Context, text string) (Result, error) {
+	result := Result{Verdict: Allow}
+
+	for _, word := range words(text) {
+		if m.rejected[word] {
+			return Result{Verdict: Reject, Reason: "termo proibido: " + word}, nil
+		}
+		if m.flagged[word] {
+			result = Result{Verdict: Flag, Reason: "termo sob revisão: " + word}
+		}
+	}
+
+	return result, nil
+}
+
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+func toSet(list []string) map[string]bool {
+	set := map[string]bool{}
+	for _, item := range list {
+		set[strings.ToLower(item)] = true
+	}
+	return set
+}
@@ -0,0 +1,58 @@
+package normalize
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/pytsx/goapi/model"
+	"golang.org/x/text/unicode/norm"
+)
+
+// homóglifos cirílicos e gregos mais comuns que se passam por letras latinas
+var latinLookalikes = map[rune]rune{
+	'а': 'a', 'в': 'B', 'е': 'e', 'к': 'k', 'м': 'M', 'н': 'H', 'о': 'o', 'р': 'p',
+	'с': 'c', 'т': 'T', 'у': 'y', 'х': 'x', 'і': 'i', 'ј': 'j', 'ѕ': 's', 'ԁ': 'd',
+	'А': 'A', 'В': 'B', 'Е': 'E', 'К': 'K', 'М': 'M', 'Н': 'H', 'О': 'O', 'Р': 'P',
+	'С': 'C', 'Т': 'T', 'У': 'Y', 'Х': 'X', 'І': 'I', 'Ј': 'J', 'Ѕ': 'S',
+	'α': 'a', 'ο': 'o', 'ρ': 'p', 'ν': 'v', 'ι': 'i', 'κ': 'k',
+	'Α': 'A', 'Β': 'B', 'Ε': 'E', 'Ζ': 'Z', 'Η': 'H', 'Ι': 'I', 'Κ': 'K', 'Μ': 'M',
+	'Ν': 'N', 'Ο': 'O', 'Ρ': 'P', 'Τ': 'T', 'Υ': 'Y', 'Χ': 'X',
+}
+
+// foldConfusables aplica NFKC (formas de largura total, ligaduras...), remove
+// caracteres invisíveis e, apenas em nomes que misturam alfabetos, troca os
+// homóglifos pela letra latina. Nomes inteiramente cirílicos ou gregos são preservados.
+func foldConfusables(user *model.User) {
+	name := norm.NFKC.String(user.Name)
+
+	name = strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Cf, r) {
+			return -1
+		}
+		return r
+	}, name)
+
+	if mixesLatin(name) {
+		name = strings.Map(func(r rune) rune {
+			if latin, ok := latinLookalikes[r]; ok {
+				return latin
+			}
+			return r
+		}, name)
+	}
+
+	user.Name = name
+}
+
+func mixesLatin(s string) bool {
+	var latin, other bool
+	for _, r := range s {
+		switch {
+		case unicode.Is(unicode.Latin, r):
+			latin = true
+		case unicode.Is(unicode.Cyrillic, r) || unicode.Is(unicode.Greek, r):
+			other = true
+		}
+	}
+	return latin && other
+}
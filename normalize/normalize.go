@@ -0,0 +1,113 @@
+package normalize
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/pytsx/goapi/model"
+)
+
+type Rule func(user *model.User)
+
+// regras disponíveis, na ordem em que são aplicadas
+var rules = []struct {
+	name string
+	rule Rule
+}{
+	{"confusables", foldConfusables},
+	{"trim", trim},
+	{"lowercase_email", lowercaseEmail},
+	{"canonical_url", canonicalURL},
+}
+
+// DefaultRules é usado quando a configuração não define NORMALIZE_RULES
+var DefaultRules = []string{"confusables", "trim", "lowercase_email", "canonical_url"}
+
+type Pipeline []Rule
+
+// NewPipeline monta o pipeline com as regras habilitadas, sempre na ordem canônica
+func NewPipeline(enabled []string) (Pipeline, error) {
+	wanted := map[string]bool{}
+	for _, name := range enabled {
+		wanted[name] = true
+	}
+
+	var pipeline Pipeline
+	for _, r := range rules {
+		if wanted[r.name] {
+			pipeline = append(pipeline, r.rule)
+			delete(wanted, r.name)
+		}
+	}
+
+	for name := range wanted {
+		return nil, fmt.Errorf("regra de normalização desconhecida: %s", name)
+	}
+
+	return pipeline, nil
+}
+
+func (p Pipeline) Apply(user *model.User) {
+	for _, rule := range p {
+		rule(user)
+	}
+}
+
+// remove espaços das pontas e colapsa espaços repetidos no nome
+func trim(user *model.User) {
+	user.Name = strings.Join(strings.Fields(user.Name), " ")
+	user.Email = strings.TrimSpace(user.Email)
+	user.ImgURL = model.NullString(strings.TrimSpace(string(user.ImgURL)))
+}
+
+func lowercaseEmail(user *model.User) {
+	user.Email = strings.ToLower(user.Email)
+}
+
+// esquema e host em minúsculas, sem porta padrão e sem fragmento
+func canonicalURL(user *model.User) {
+	if user.ImgURL == "" {
+		return
+	}
+
+	parsed, err := url.Parse(string(user.ImgURL))
+	if err != nil || parsed.Host == "" {
+		// a validação se encarrega de rejeitar
+		return
+	}
+
+	parsed.Scheme = strings.ToLower(parsed.Scheme)
+	host := strings.ToLower(parsed.Hostname())
+	port := parsed.Port()
+	if (parsed.Scheme == "http" && port == "80") || (parsed.Scheme == "https" && port == "443") {
+		port = ""
+	}
+
+	parsed.Host = host
+	if port != "" {
+		parsed.Host = host + ":" + port
+	}
+	parsed.Fragment = ""
+	parsed.RawFragment = ""
+
+	user.ImgURL = model.NullString(parsed.String())
+}
+
+// CanonicalEmail reduz variações que entregam na mesma caixa postal: minúsculas,
+// sem sufixo +tag e, no Gmail, sem pontos na parte local. Usado apenas para
+// detectar duplicados; o email gravado continua sendo o informado.
+func CanonicalEmail(email string) string {
+	local, domain, ok := strings.Cut(strings.ToLower(strings.TrimSpace(email)), "@")
+	if !ok {
+		return strings.ToLower(strings.TrimSpace(email))
+	}
+
+	local, _, _ = strings.Cut(local, "+")
+	if domain == "gmail.com" || domain == "googlemail.com" {
+		local = strings.ReplaceAll(local, ".", "")
+		domain = "gmail.com"
+	}
+
+	return local + "@" + domain
+}
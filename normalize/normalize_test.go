@@ -0,0 +1,144 @@
+package normalize
+
+import (
+	"testing"
+
+	"github.com/pytsx/goapi/model"
+)
+
+func TestTrim(t *testing.T) {
+	tests := []struct {
+		name string
+		in   model.User
+		want model.User
+	}{
+		{"pontas", model.User{Name: "  Ana  ", Email: " ana@x.com ", ImgURL: " https://x.com/a.png "},
+			model.User{Name: "Ana", Email: "ana@x.com", ImgURL: "https://x.com/a.png"}},
+		{"espaços repetidos no nome", model.User{Name: "Ana \t  Maria\nSilva"},
+			model.User{Name: "Ana Maria Silva"}},
+		{"vazio", model.User{}, model.User{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := tt.in
+			trim(&user)
+			if user.Name != tt.want.Name || user.Email != tt.want.Email || user.ImgURL != tt.want.ImgURL {
+				t.Errorf("trim(%+v) = %+v, esperado %+v", tt.in, user, tt.want)
+			}
+		})
+	}
+}
+
+func TestLowercaseEmail(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Foo@Example.COM", "foo@example.com"},
+		{"foo@example.com", "foo@example.com"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		user := model.User{Email: tt.in}
+		lowercaseEmail(&user)
+		if user.Email != tt.want {
+			t.Errorf("lowercaseEmail(%q) = %q, esperado %q", tt.in, user.Email, tt.want)
+		}
+	}
+}
+
+func TestCanonicalURL(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"esquema e host em minúsculas", "HTTPS://Example.COM/Avatar.png", "https://example.com/Avatar.png"},
+		{"porta padrão http", "http://example.com:80/a.png", "http://example.com/a.png"},
+		{"porta padrão https", "https://example.com:443/a.png", "https://example.com/a.png"},
+		{"outra porta é mantida", "https://example.com:8443/a.png", "https://example.com:8443/a.png"},
+		{"sem fragmento", "https://example.com/a.png#topo", "https://example.com/a.png"},
+		{"query é mantida", "https://example.com/a.png?v=2", "https://example.com/a.png?v=2"},
+		{"sem host fica para a validação", "avatar.png", "avatar.png"},
+		{"vazio", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := model.User{ImgURL: model.NullString(tt.in)}
+			canonicalURL(&user)
+			if string(user.ImgURL) != tt.want {
+				t.Errorf("canonicalURL(%q) = %q, esperado %q", tt.in, user.ImgURL, tt.want)
+			}
+		})
+	}
+}
+
+func TestFoldConfusables(t *testing.T) {
+	tests := []struct {
+		name, in, want string
+	}{
+		{"cirílico misturado ao latim", "Pаypal", "Paypal"},
+		{"grego misturado ao latim", "Bοb", "Bob"},
+		{"nome inteiramente cirílico é preservado", "Анна", "Анна"},
+		{"nome inteiramente grego é preservado", "Αλέξης", "Αλέξης"},
+		{"largura total (NFKC)", "Ａｎａ", "Ana"},
+		{"caracteres invisíveis", "An\u200ba\u200d", "Ana"},
+		{"latim com acentos", "José", "José"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := model.User{Name: tt.in}
+			foldConfusables(&user)
+			if user.Name != tt.want {
+				t.Errorf("foldConfusables(%q) = %q, esperado %q", tt.in, user.Name, tt.want)
+			}
+		})
+	}
+}
+
+func TestMixesLatin(t *testing.T) {
+	tests := []struct {
+		in   string
+		want bool
+	}{
+		{"Ana", false},
+		{"Анна", false},
+		{"Αλέξης", false},
+		{"Pаypal", true},
+		{"Bοb", true},
+		{"123 !", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		if got := mixesLatin(tt.in); got != tt.want {
+			t.Errorf("mixesLatin(%q) = %v, esperado %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNewPipeline(t *testing.T) {
+	if _, err := NewPipeline([]string{"trim", "desconhecida"}); err == nil {
+		t.Error("NewPipeline com regra desconhecida: esperado erro")
+	}
+
+	pipeline, err := NewPipeline(DefaultRules)
+	if err != nil {
+		t.Fatalf("NewPipeline(DefaultRules): %v", err)
+	}
+	if len(pipeline) != len(DefaultRules) {
+		t.Errorf("NewPipeline(DefaultRules) montou %d regras, esperado %d", len(pipeline), len(DefaultRules))
+	}
+
+	// a ordem canônica vale mesmo com as regras fora de ordem na configuração
+	pipeline, err = NewPipeline([]string{"lowercase_email", "trim"})
+	if err != nil {
+		t.Fatalf("NewPipeline: %v", err)
+	}
+	user := model.User{Name: "  Ana ", Email: "  Ana@X.com  "}
+	pipeline.Apply(&user)
+	if user.Name != "Ana" || user.Email != "ana@x.com" {
+		t.Errorf("Apply = %+v", user)
+	}
+}
@@ -0,0 +1,71 @@
+package presence
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Tracker registra os heartbeats dos usuários. Um usuário é considerado online
+// enquanto o último heartbeat tiver menos que o TTL configurado.
+type Tracker interface {
+	Heartbeat(ctx context.Context, userID int) error
+	// Online indica, para cada id informado, se o usuário está online
+	Online(ctx context.Context, userIDs []int) (map[int]bool, error)
+	// List devolve os ids de todos os usuários online, em ordem crescente
+	List(ctx context.Context) ([]int, error)
+}
+
+type MemoryTracker struct {
+	ttl      time.Duration
+	mu       sync.RWMutex
+	lastSeen map[int]time.Time
+}
+
+func NewMemoryTracker(ttl time.Duration) *MemoryTracker {
+	return &MemoryTracker{
+		ttl:      ttl,
+		lastSeen: map[int]time.Time{},
+	}
+}
+
+func (t *MemoryTracker) Heartbeat(_ context.Context, userID int) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	for id, seen := range t.lastSeen {
+		if now.Sub(seen) >= t.ttl {
+			delete(t.lastSeen, id)
+		}
+	}
+	t.lastSeen[userID] = now
+	return nil
+}
+
+func (t *MemoryTracker) Online(_ context.Context, userIDs []int) (map[int]bool, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	online := make(map[int]bool, len(userIDs))
+	for _, id := range userIDs {
+		seen, ok := t.lastSeen[id]
+		online[id] = ok && time.Since(seen) < t.ttl
+	}
+	return online, nil
+}
+
+func (t *MemoryTracker) List(_ context.Context) ([]int, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	ids := []int{}
+	for id, seen := range t.lastSeen {
+		if time.Since(seen) < t.ttl {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
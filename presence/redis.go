@@ -0,0 +1,74 @@
+package presence
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const keyPrefix = "presence:user:"
+
+// RedisTracker guarda uma chave com TTL por usuário, compartilhada entre as instâncias da API
+type RedisTracker struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+func NewRedisTracker(client *redis.Client, ttl time.Duration) RedisTracker {
+	return RedisTracker{
+		client: client,
+		ttl:    ttl,
+	}
+}
+
+func (t RedisTracker) Heartbeat(ctx context.Context, userID int) error {
+	return t.client.Set(ctx, keyPrefix+strconv.Itoa(userID), time.Now().Unix(), t.ttl).Err()
+}
+
+func (t RedisTracker) Online(ctx context.Context, userIDs []int) (map[int]bool, error) {
+	online := make(map[int]bool, len(userIDs))
+	if len(userIDs) == 0 {
+		return online, nil
+	}
+
+	keys := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		keys[i] = keyPrefix + strconv.Itoa(id)
+	}
+
+	values, err := t.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	for i, id := range userIDs {
+		online[id] = values[i] != nil
+	}
+	return online, nil
+}
+
+func (t RedisTracker) List(ctx context.Context) ([]int, error) {
+	ids := []int{}
+	iter := t.client.Scan(ctx, 0, keyPrefix+"*", 500).Iterator()
+	for iter.Next(ctx) {
+		id, err := strconv.Atoi(strings.TrimPrefix(iter.Val(), keyPrefix))
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if err := iter.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Ints(ids)
+	return ids, nil
+}
+
+// Ping verifica se o Redis está acessível
+func (t RedisTracker) Ping(ctx context.Context) error {
+	return t.client.Ping(ctx).Err()
+}
@@ -0,0 +1,58 @@
+package quota
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync/atomic"
+	"time"
+)
+
+// Counter contabiliza as requisições diárias de cada chave de API
+type Counter interface {
+	// Increment soma uma requisição ao dia e devolve o total acumulado
+	Increment(ctx context.Context, keyID string, day time.Time) (int64, error)
+	Usage(ctx context.Context, keyIDs []string, day time.Time) (map[string]int64, error)
+}
+
+// KeyID identifica uma chave de API nos contadores e relatórios sem expor o segredo
+func KeyID(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// Today devolve o dia corrente (UTC) usado nos contadores
+func Today() time.Time {
+	return time.Now().UTC().Truncate(24 * time.Hour)
+}
+
+// NextReset devolve o instante em que os contadores do dia são zerados
+func NextReset() time.Time {
+	return Today().Add(24 * time.Hour)
+}
+
+// Limits guarda os limites vigentes, que podem ser trocados em tempo de execução
+// pela importação de configuração. Zero desativa o limite.
+type Limits struct {
+	maxUsers      atomic.Int64
+	dailyRequests atomic.Int64
+}
+
+func NewLimits(maxUsers, dailyRequests int) *Limits {
+	limits := &Limits{}
+	limits.Set(maxUsers, dailyRequests)
+	return limits
+}
+
+func (l *Limits) Set(maxUsers, dailyRequests int) {
+	l.maxUsers.Store(int64(maxUsers))
+	l.dailyRequests.Store(int64(dailyRequests))
+}
+
+func (l *Limits) MaxUsers() int {
+	return int(l.maxUsers.Load())
+}
+
+func (l *Limits) DailyRequests() int {
+	return int(l.dailyRequests.Load())
+}
@@ -0,0 +1,68 @@
+package quota
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const keyPrefix = "quota:requests:"
+
+// RedisCounter mantém um contador por chave e dia, que expira depois do fim do dia
+type RedisCounter struct {
+	client *redis.Client
+}
+
+func NewRedisCounter(client *redis.Client) RedisCounter {
+	return RedisCounter{
+		client: client,
+	}
+}
+
+func counterKey(keyID string, day time.Time) string {
+	return keyPrefix + keyID + ":" + day.Format(time.DateOnly)
+}
+
+func (c RedisCounter) Increment(ctx context.Context, keyID string, day time.Time) (int64, error) {
+	key := counterKey(keyID, day)
+
+	pipe := c.client.TxPipeline()
+	incr := pipe.Incr(ctx, key)
+	pipe.ExpireNX(ctx, key, 48*time.Hour)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+
+	return incr.Val(), nil
+}
+
+func (c RedisCounter) Usage(ctx context.Context, keyIDs []string, day time.Time) (map[string]int64, error) {
+	usage := make(map[string]int64, len(keyIDs))
+	if len(keyIDs) == 0 {
+		return usage, nil
+	}
+
+	keys := make([]string, len(keyIDs))
+	for i, id := range keyIDs {
+		keys[i] = counterKey(id, day)
+	}
+
+	values, err := c.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+	for i, id := range keyIDs {
+		value, ok := values[i].(string)
+		if !ok {
+			continue
+		}
+		count, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		usage[id] = count
+	}
+	return usage, nil
+}
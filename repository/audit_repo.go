@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+
+	"github.com/pytsx/goapi/model"
+)
+
+type AuditRepository struct {
+	connection DBTX
+}
+
+func NewAuditRepository(conn *sql.DB) AuditRepository {
+	return AuditRepository{
+		connection: conn,
+	}
+}
+
+// WithContext vincula os comandos ao contexto da requisição (deadline e cancelamento)
+func (ar AuditRepository) WithContext(ctx context.Context) AuditRepository {
+	return AuditRepository{
+		connection: bindContext(ar.connection, ctx),
+	}
+}
+
+func (ar AuditRepository) WithTx(tx *sql.Tx) AuditRepository {
+	return AuditRepository{
+		connection: tx,
+	}
+}
+
+func (ar *AuditRepository) Record(entry model.AuditEntry) error {
+	metadata, err := json.Marshal(entry.Metadata)
+	if err != nil {
+		return err
+	}
+
+	_, err = ar.connection.Exec("INSERT INTO audit_log"+
+		" (actor, action, entity, entity_id, ip, country, metadata)"+
+		" VALUES ($1, $2, $3, $4, $5, $6, $7)",
+		entry.Actor.ID, entry.Action, entry.Entity, entry.EntityID,
+		entry.Actor.IP, entry.Actor.Country, metadata)
+
+	return err
+}
+
+// ListForUser devolve as entradas mais recentes feitas pelo usuário ou sobre ele
+func (ar *AuditRepository) ListForUser(userID, limit int) ([]model.AuditEntry, error) {
+	id := strconv.Itoa(userID)
+	rows, err := ar.connection.Query("SELECT id, actor, action, entity, entity_id, ip, country, metadata, created_at"+
+		" FROM audit_log WHERE actor = $1 OR (entity = 'user' AND entity_id = $2)"+
+		" ORDER BY created_at DESC, id DESC LIMIT $3", "user:"+id, id, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []model.AuditEntry{}
+	for rows.Next() {
+		var entry model.AuditEntry
+		var metadata []byte
+		err := rows.Scan(
+			&entry.ID,
+			&entry.Actor.ID,
+			&entry.Action,
+			&entry.Entity,
+			&entry.EntityID,
+			&entry.Actor.IP,
+			&entry.Actor.Country,
+			&metadata,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(metadata, &entry.Metadata); err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/pytsx/goapi/model"
+)
+
+type ConsentRepository struct {
+	connection *sql.DB
+}
+
+func NewConsentRepository(conn *sql.DB) ConsentRepository {
+	return ConsentRepository{
+		connection: conn,
+	}
+}
+
+func (cr *ConsentRepository) GetConsents(userID int) ([]model.Consent, error) {
+	rows, err := cr.connection.Query("SELECT policy, version, accepted_at FROM consents"+
+		" WHERE user_id = $1 ORDER BY accepted_at", userID)
+	if err != nil {
+		return []model.Consent{}, err
+	}
+	defer rows.Close()
+
+	consents := []model.Consent{}
+	for rows.Next() {
+		var consent model.Consent
+		if err := rows.Scan(&consent.Policy, &consent.Version, &consent.AcceptedAt); err != nil {
+			return []model.Consent{}, err
+		}
+		consents = append(consents, consent)
+	}
+
+	return consents, rows.Err()
+}
+
+// RecordConsent é idempotente: aceitar a mesma versão novamente não gera outro registro
+func (cr *ConsentRepository) RecordConsent(userID int, consent model.Consent, ip string) error {
+	_, err := cr.connection.Exec("INSERT INTO consents (user_id, policy, version, ip)"+
+		" VALUES ($1, $2, $3, $4) ON CONFLICT (user_id, policy, version) DO NOTHING",
+		userID, consent.Policy, consent.Version, ip)
+
+	return err
+}
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pytsx/goapi/model"
+)
+
+type InvitationRepository struct {
+	connection DBTX
+}
+
+func NewInvitationRepository(conn *sql.DB) InvitationRepository {
+	return InvitationRepository{
+		connection: conn,
+	}
+}
+
+// WithContext vincula os comandos ao contexto da requisição (deadline e cancelamento)
+func (ir InvitationRepository) WithContext(ctx context.Context) InvitationRepository {
+	return InvitationRepository{
+		connection: bindContext(ir.connection, ctx),
+	}
+}
+
+func (ir InvitationRepository) WithTx(tx *sql.Tx) InvitationRepository {
+	return InvitationRepository{
+		connection: tx,
+	}
+}
+
+const invitationColumns = "id, code_hint, note, max_uses, uses, expires_at, revoked_at, created_by, created_at"
+
+func (ir *InvitationRepository) Create(codeHash, codeHint string, request model.InvitationRequest, actor string) (model.Invitation, error) {
+	row := ir.connection.QueryRow("INSERT INTO invitations (code_hash, code_hint, note, max_uses, expires_at, created_by)"+
+		" VALUES ($1, $2, $3, $4, $5, $6) RETURNING "+invitationColumns,
+		codeHash, codeHint, request.Note, request.MaxUses, request.ExpiresAt, actor)
+
+	invitation, err := scanInvitation(row)
+	if err != nil {
+		return model.Invitation{}, err
+	}
+	return *invitation, nil
+}
+
+func (ir *InvitationRepository) GetInvitation(id int64) (*model.Invitation, error) {
+	row := ir.connection.QueryRow("SELECT "+invitationColumns+" FROM invitations WHERE id = $1", id)
+
+	invitation, err := scanInvitation(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return invitation, err
+}
+
+// List devolve os convites mais recentes primeiro
+func (ir *InvitationRepository) List(limit, offset int) ([]model.Invitation, error) {
+	rows, err := ir.connection.Query("SELECT "+invitationColumns+" FROM invitations"+
+		" ORDER BY id DESC LIMIT $1 OFFSET $2", limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	invitations := []model.Invitation{}
+	for rows.Next() {
+		invitation, err := scanInvitation(rows)
+		if err != nil {
+			return nil, err
+		}
+		invitations = append(invitations, *invitation)
+	}
+	return invitations, rows.Err()
+}
+
+// Revoke retorna false quando o convite não existe; revogar de novo mantém a data original
+func (ir *InvitationRepository) Revoke(id int64) (bool, error) {
+	result, err := ir.connection.Exec("UPDATE invitations SET revoked_at = COALESCE(revoked_at, now())"+
+		" WHERE id = $1", id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// Redeem consome um uso do convite, se ele ainda for válido, e devolve o id. O UPDATE
+// condicional impede que cadastros concorrentes ultrapassem max_uses. Retorna 0 quando
+// o código não existe, expirou, se esgotou ou foi revogado
+func (ir *InvitationRepository) Redeem(codeHash string) (int64, error) {
+	var id int64
+	err := ir.connection.QueryRow("UPDATE invitations SET uses = uses + 1"+
+		" WHERE code_hash = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > now())"+
+		" AND uses < max_uses RETURNING id", codeHash).Scan(&id)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return id, err
+}
+
+func (ir *InvitationRepository) RecordUse(invitationID int64, userID int) error {
+	_, err := ir.connection.Exec("INSERT INTO invitation_uses (invitation_id, user_id) VALUES ($1, $2)",
+		invitationID, userID)
+	return err
+}
+
+func (ir *InvitationRepository) GetUses(invitationID int64) ([]model.InvitationUse, error) {
+	rows, err := ir.connection.Query("SELECT COALESCE(user_id, 0), used_at FROM invitation_uses"+
+		" WHERE invitation_id = $1 ORDER BY used_at, id", invitationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	uses := []model.InvitationUse{}
+	for rows.Next() {
+		var use model.InvitationUse
+		if err := rows.Scan(&use.UserID, &use.UsedAt); err != nil {
+			return nil, err
+		}
+		uses = append(uses, use)
+	}
+	return uses, rows.Err()
+}
+
+func scanInvitation(row rowScanner) (*model.Invitation, error) {
+	var invitation model.Invitation
+	err := row.Scan(
+		&invitation.ID,
+		&invitation.CodeHint,
+		&invitation.Note,
+		&invitation.MaxUses,
+		&invitation.Uses,
+		&invitation.ExpiresAt,
+		&invitation.RevokedAt,
+		&invitation.CreatedBy,
+		&invitation.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
@@ -0,0 +1,218 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pytsx/goapi/model"
+)
+
+const jobColumns = "id, queue, kind, payload, status, attempts, max_attempts, last_error, run_at, created_at, updated_at"
+
+type JobRepository struct {
+	connection DBTX
+}
+
+func NewJobRepository(conn *sql.DB) JobRepository {
+	return JobRepository{
+		connection: conn,
+	}
+}
+
+// WithContext vincula os comandos ao contexto da requisição (deadline e cancelamento)
+func (jr JobRepository) WithContext(ctx context.Context) JobRepository {
+	return JobRepository{
+		connection: bindContext(jr.connection, ctx),
+	}
+}
+
+func (jr JobRepository) WithTx(tx *sql.Tx) JobRepository {
+	return JobRepository{
+		connection: tx,
+	}
+}
+
+func (jr *JobRepository) Enqueue(job model.Job) (int64, error) {
+	var id int64
+
+	if job.Queue == "" {
+		job.Queue = "default"
+	}
+	if job.MaxAttempts == 0 {
+		job.MaxAttempts = 5
+	}
+
+	err := jr.connection.QueryRow("INSERT INTO jobs (queue, kind, payload, max_attempts)"+
+		" VALUES ($1, $2, $3, $4) RETURNING id",
+		job.Queue, job.Kind, []byte(job.Payload), job.MaxAttempts).Scan(&id)
+	if err != nil {
+		return -1, err
+	}
+
+	return id, nil
+}
+
+// ClaimNext reserva o próximo job pendente da fila; SKIP LOCKED permite vários workers em paralelo.
+// Jobs em execução sem Heartbeat há mais de `lease` (o worker caiu) são retomados.
+// Retorna nil quando não há nada a processar.
+func (jr *JobRepository) ClaimNext(queue string, lease time.Duration) (*model.Job, error) {
+	row := jr.connection.QueryRow("UPDATE jobs SET status = 'running', attempts = attempts + 1, updated_at = now()"+
+		" WHERE id = (SELECT id FROM jobs WHERE queue = $1"+
+		" AND ((status = 'pending' AND run_at <= now())"+
+		" OR (status = 'running' AND updated_at < now() - make_interval(secs => $2)))"+
+		" ORDER BY run_at LIMIT 1 FOR UPDATE SKIP LOCKED)"+
+		" RETURNING "+jobColumns, queue, lease.Seconds())
+
+	job, err := scanJob(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+
+	return job, err
+}
+
+// Heartbeat renova a reserva do job enquanto ele executa
+func (jr *JobRepository) Heartbeat(id int64) error {
+	_, err := jr.connection.Exec("UPDATE jobs SET updated_at = now() WHERE id = $1 AND status = 'running'", id)
+
+	return err
+}
+
+func (jr *JobRepository) Complete(id int64) error {
+	_, err := jr.connection.Exec("UPDATE jobs SET status = 'done', last_error = '', updated_at = now()"+
+		" WHERE id = $1", id)
+
+	return err
+}
+
+// Fail devolve o job para a fila em `retryAt` ou, sem novas tentativas, o move para a fila morta
+func (jr *JobRepository) Fail(id int64, cause string, retryAt *time.Time) error {
+	if retryAt == nil {
+		_, err := jr.connection.Exec("UPDATE jobs SET status = 'dead', last_error = $2, updated_at = now()"+
+			" WHERE id = $1", id, cause)
+		return err
+	}
+
+	_, err := jr.connection.Exec("UPDATE jobs SET status = 'pending', last_error = $2, run_at = $3, updated_at = now()"+
+		" WHERE id = $1", id, cause, *retryAt)
+
+	return err
+}
+
+// Stats conta os jobs de cada fila por status
+func (jr *JobRepository) Stats() ([]model.JobQueueStats, error) {
+	rows, err := jr.connection.Query("SELECT queue," +
+		" count(*) FILTER (WHERE status = 'pending')," +
+		" count(*) FILTER (WHERE status = 'pending' AND attempts > 0)," +
+		" count(*) FILTER (WHERE status = 'running')," +
+		" count(*) FILTER (WHERE status = 'done')," +
+		" count(*) FILTER (WHERE status = 'dead')," +
+		" min(run_at) FILTER (WHERE status = 'pending')" +
+		" FROM jobs GROUP BY queue ORDER BY queue")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := []model.JobQueueStats{}
+	for rows.Next() {
+		var queue model.JobQueueStats
+		err := rows.Scan(
+			&queue.Queue,
+			&queue.Pending,
+			&queue.Retrying,
+			&queue.Running,
+			&queue.Done,
+			&queue.Dead,
+			&queue.OldestAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, queue)
+	}
+
+	return stats, rows.Err()
+}
+
+// List devolve os jobs mais recentes que atendem ao filtro; campos vazios não filtram
+func (jr *JobRepository) List(filter model.JobFilter, limit, offset int) ([]model.Job, error) {
+	query, args := selectFrom("jobs", jobColumns).
+		WhereIf(filter.Queue != "", "queue = ?", filter.Queue).
+		WhereIf(filter.Kind != "", "kind = ?", filter.Kind).
+		WhereIf(filter.Status != "", "status = ?", filter.Status).
+		OrderBy("id DESC").
+		Limit(limit).
+		Offset(offset).
+		Build()
+
+	rows, err := jr.connection.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	jobs := []model.Job{}
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, *job)
+	}
+
+	return jobs, rows.Err()
+}
+
+func (jr *JobRepository) GetJob(id int64) (*model.Job, error) {
+	row := jr.connection.QueryRow("SELECT "+jobColumns+" FROM jobs WHERE id = $1", id)
+
+	job, err := scanJob(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return job, err
+}
+
+// Retry devolve um job da fila morta para a fila, com as tentativas zeradas.
+// Retorna false quando o job não existe ou não está na fila morta.
+func (jr *JobRepository) Retry(id int64) (bool, error) {
+	result, err := jr.connection.Exec("UPDATE jobs SET status = 'pending', attempts = 0, run_at = now(), updated_at = now()"+
+		" WHERE id = $1 AND status = 'dead'", id)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanJob(row rowScanner) (*model.Job, error) {
+	var job model.Job
+	var payload []byte
+
+	err := row.Scan(
+		&job.ID,
+		&job.Queue,
+		&job.Kind,
+		&payload,
+		&job.Status,
+		&job.Attempts,
+		&job.MaxAttempts,
+		&job.LastError,
+		&job.RunAt,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	job.Payload = payload
+	return &job, nil
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/pytsx/goapi/model"
+)
+
+type LoginRepository struct {
+	connection *sql.DB
+}
+
+func NewLoginRepository(conn *sql.DB) LoginRepository {
+	return LoginRepository{
+		connection: conn,
+	}
+}
+
+func (lr *LoginRepository) RecordLogin(login model.Login) (int, error) {
+	var id int
+
+	err := lr.connection.QueryRow("INSERT INTO login_history "+
+		"(user_id, device, ip, country, city, location)"+
+		" VALUES ($1, $2, $3, $4, $5, $6) RETURNING id",
+		login.UserID, login.Device, login.IP, login.Country, login.City, login.Location,
+	).Scan(&id)
+	if err != nil {
+		return -1, err
+	}
+
+	return id, nil
+}
+
+// KnownLogin informa se o usuário tem histórico e se já entrou deste dispositivo e deste local
+func (lr *LoginRepository) KnownLogin(login model.Login) (hasHistory, knownDevice, knownLocation bool, err error) {
+	err = lr.connection.QueryRow("SELECT COUNT(*) > 0,"+
+		" COUNT(*) FILTER (WHERE device = $2) > 0,"+
+		" COUNT(*) FILTER (WHERE location = $3) > 0"+
+		" FROM login_history WHERE user_id = $1",
+		login.UserID, login.Device, login.Location,
+	).Scan(&hasHistory, &knownDevice, &knownLocation)
+
+	return hasHistory, knownDevice, knownLocation, err
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+type MaintenanceRepository struct {
+	connection *sql.DB
+}
+
+func NewMaintenanceRepository(conn *sql.DB) MaintenanceRepository {
+	return MaintenanceRepository{
+		connection: conn,
+	}
+}
+
+// Reindex reconstrói os índices da tabela users sem bloquear escritas.
+// REINDEX CONCURRENTLY não pode rodar dentro de uma transação.
+func (mr *MaintenanceRepository) Reindex(ctx context.Context) error {
+	return withoutStatementTimeout(ctx, mr.connection, func(conn *sql.Conn) error {
+		_, err := conn.ExecContext(ctx, "REINDEX TABLE CONCURRENTLY users")
+		return err
+	})
+}
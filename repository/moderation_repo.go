@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/pytsx/goapi/model"
+)
+
+type ModerationRepository struct {
+	connection *sql.DB
+}
+
+func NewModerationRepository(conn *sql.DB) ModerationRepository {
+	return ModerationRepository{
+		connection: conn,
+	}
+}
+
+func (mr *ModerationRepository) GetCases(status model.ModerationStatus) ([]model.ModerationCase, error) {
+	rows, err := mr.connection.Query("SELECT id, name, email, moderation_status, moderation_reason, moderated_at"+
+		" FROM users WHERE moderation_status = $1 ORDER BY id", status)
+	if err != nil {
+		return []model.ModerationCase{}, err
+	}
+	defer rows.Close()
+
+	cases := []model.ModerationCase{}
+	for rows.Next() {
+		var c model.ModerationCase
+		err := rows.Scan(&c.UserID, &c.Name, &c.Email, &c.Status, &c.Reason, &c.ModeratedAt)
+		if err != nil {
+			return []model.ModerationCase{}, err
+		}
+		cases = append(cases, c)
+	}
+
+	return cases, rows.Err()
+}
+
+// UpdateStatus retorna false quando o usuário não existe
+func (mr *ModerationRepository) UpdateStatus(userID int, status model.ModerationStatus) (bool, error) {
+	result, err := mr.connection.Exec("UPDATE users SET moderation_status = $2, moderated_at = now()"+
+		" WHERE id = $1", userID, status)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	return affected > 0, err
+}
+
+// QuarantineAvatar registra o avatar reprovado; a imagem atual do usuário é mantida
+func (mr *ModerationRepository) QuarantineAvatar(userID int, key, reason string) error {
+	_, err := mr.connection.Exec("UPDATE users SET avatar_status = 'quarantined', avatar_reason = $2,"+
+		" avatar_quarantine_key = $3 WHERE id = $1", userID, reason, key)
+
+	return err
+}
+
+func (mr *ModerationRepository) GetUserModeration(userID int) (*model.UserModeration, error) {
+	var m model.UserModeration
+
+	err := mr.connection.QueryRow("SELECT moderation_status, moderation_reason, moderated_at,"+
+		" avatar_status, avatar_reason, avatar_quarantine_key FROM users WHERE id = $1", userID).Scan(
+		&m.NameStatus,
+		&m.NameReason,
+		&m.ModeratedAt,
+		&m.AvatarStatus,
+		&m.AvatarReason,
+		&m.AvatarQuarantineKey,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &m, nil
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"database/sql"
+
+	"github.com/pytsx/goapi/model"
+)
+
+type PreferencesRepository struct {
+	connection *sql.DB
+}
+
+func NewPreferencesRepository(conn *sql.DB) PreferencesRepository {
+	return PreferencesRepository{
+		connection: conn,
+	}
+}
+
+// GetPreferences retorna os valores padrão quando o usuário nunca alterou suas preferências
+func (pr *PreferencesRepository) GetPreferences(userID int) (model.Preferences, error) {
+	prefs := model.Preferences{LoginAlerts: true}
+
+	err := pr.connection.QueryRow("SELECT login_alerts, locale FROM user_preferences WHERE user_id = $1", userID).
+		Scan(&prefs.LoginAlerts, &prefs.Locale)
+	if err != nil && err != sql.ErrNoRows {
+		return model.Preferences{}, err
+	}
+
+	return prefs, nil
+}
+
+func (pr *PreferencesRepository) UpdatePreferences(userID int, prefs model.Preferences) error {
+	_, err := pr.connection.Exec("INSERT INTO user_preferences (user_id, login_alerts, locale) VALUES ($1, $2, $3)"+
+		" ON CONFLICT (user_id) DO UPDATE SET login_alerts = EXCLUDED.login_alerts, locale = EXCLUDED.locale",
+		userID, prefs.LoginAlerts, prefs.Locale)
+
+	return err
+}
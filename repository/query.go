@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"strconv"
+	"strings"
+)
+
+// selectQuery monta um SELECT a partir de partes opcionais. Valores sempre viram
+// parâmetros ($1, $2, ...); no SQL só entram trechos escritos no próprio código,
+// nunca dados da requisição.
+type selectQuery struct {
+	columns string
+	table   string
+	where   []string
+	orderBy []string
+	args    []any
+	limit   *int
+	offset  *int
+}
+
+func selectFrom(table, columns string) *selectQuery {
+	return &selectQuery{
+		columns: columns,
+		table:   table,
+	}
+}
+
+// Where adiciona uma condição combinada com AND; cada ? recebe o próximo valor de args
+func (q *selectQuery) Where(condition string, args ...any) *selectQuery {
+	var sql strings.Builder
+	next := 0
+	for _, char := range condition {
+		if char == '?' && next < len(args) {
+			q.args = append(q.args, args[next])
+			sql.WriteString("$" + strconv.Itoa(len(q.args)))
+			next++
+			continue
+		}
+		sql.WriteRune(char)
+	}
+
+	q.where = append(q.where, "("+sql.String()+")")
+	return q
+}
+
+// WhereIf adiciona a condição apenas quando ok, para filtros opcionais
+func (q *selectQuery) WhereIf(ok bool, condition string, args ...any) *selectQuery {
+	if ok {
+		return q.Where(condition, args...)
+	}
+	return q
+}
+
+// OrderBy recebe expressões fixas do código, como "created_at DESC"
+func (q *selectQuery) OrderBy(expressions ...string) *selectQuery {
+	q.orderBy = append(q.orderBy, expressions...)
+	return q
+}
+
+func (q *selectQuery) Limit(limit int) *selectQuery {
+	q.limit = &limit
+	return q
+}
+
+func (q *selectQuery) Offset(offset int) *selectQuery {
+	q.offset = &offset
+	return q
+}
+
+// Build devolve o SQL e os argumentos na ordem dos parâmetros
+func (q *selectQuery) Build() (string, []any) {
+	args := append([]any{}, q.args...)
+
+	var sql strings.Builder
+	sql.WriteString("SELECT " + q.columns + " FROM " + q.table)
+	if len(q.where) > 0 {
+		sql.WriteString(" WHERE " + strings.Join(q.where, " AND "))
+	}
+	if len(q.orderBy) > 0 {
+		sql.WriteString(" ORDER BY " + strings.Join(q.orderBy, ", "))
+	}
+	if q.limit != nil {
+		args = append(args, *q.limit)
+		sql.WriteString(" LIMIT $" + strconv.Itoa(len(args)))
+	}
+	if q.offset != nil {
+		args = append(args, *q.offset)
+		sql.WriteString(" OFFSET $" + strconv.Itoa(len(args)))
+	}
+
+	return sql.String(), args
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/pytsx/goapi/model"
+)
+
+// RuntimeConfigRepository guarda cada importação como uma nova revisão
+type RuntimeConfigRepository struct {
+	connection DBTX
+}
+
+func NewRuntimeConfigRepository(conn *sql.DB) RuntimeConfigRepository {
+	return RuntimeConfigRepository{
+		connection: conn,
+	}
+}
+
+func (rr RuntimeConfigRepository) WithTx(tx *sql.Tx) RuntimeConfigRepository {
+	return RuntimeConfigRepository{
+		connection: tx,
+	}
+}
+
+// Latest devolve a revisão mais recente, ou nil se nada foi importado
+func (rr *RuntimeConfigRepository) Latest() (*model.RuntimeConfig, error) {
+	var revision int64
+	var document []byte
+
+	err := rr.connection.QueryRow("SELECT revision, document FROM runtime_config"+
+		" ORDER BY revision DESC LIMIT 1").Scan(&revision, &document)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var config model.RuntimeConfig
+	if err := json.Unmarshal(document, &config); err != nil {
+		return nil, err
+	}
+	config.Revision = revision
+	return &config, nil
+}
+
+// Lock serializa as importações concorrentes até o fim da transação
+func (rr *RuntimeConfigRepository) Lock() error {
+	_, err := rr.connection.Exec("LOCK TABLE runtime_config IN EXCLUSIVE MODE")
+	return err
+}
+
+func (rr *RuntimeConfigRepository) Save(config model.RuntimeConfig, actor string) (int64, error) {
+	config.Revision = 0
+	config.ExportedAt = nil
+	document, err := json.Marshal(config)
+	if err != nil {
+		return -1, err
+	}
+
+	var revision int64
+	err = rr.connection.QueryRow("INSERT INTO runtime_config (schema_version, document, actor)"+
+		" VALUES ($1, $2, $3) RETURNING revision", config.SchemaVersion, document, actor).Scan(&revision)
+	if err != nil {
+		return -1, err
+	}
+
+	return revision, nil
+}
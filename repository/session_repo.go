@@ -0,0 +1,179 @@
+package repository
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/pytsx/goapi/model"
+)
+
+type SessionRepository struct {
+	connection *sql.DB
+}
+
+func NewSessionRepository(conn *sql.DB) SessionRepository {
+	return SessionRepository{
+		connection: conn,
+	}
+}
+
+func (sr *SessionRepository) CreateSession(session model.Session) error {
+	_, err := sr.connection.Exec("INSERT INTO sessions "+
+		"(id, user_id, refresh_token_hash, device, ip, expires_at)"+
+		" VALUES ($1, $2, $3, $4, $5, $6)",
+		session.ID, session.UserID, session.RefreshTokenHash, session.Device, session.IP, session.ExpiresAt)
+
+	return err
+}
+
+// GetActiveSession retorna nil quando a sessão não existe, expirou ou foi revogada
+func (sr *SessionRepository) GetActiveSession(id string) (*model.Session, error) {
+	var session model.Session
+
+	err := sr.connection.QueryRow("SELECT id, user_id, refresh_token_hash, device, ip, created_at, last_seen_at, expires_at"+
+		" FROM sessions WHERE id = $1 AND revoked_at IS NULL AND expires_at > now()", id).Scan(
+		&session.ID,
+		&session.UserID,
+		&session.RefreshTokenHash,
+		&session.Device,
+		&session.IP,
+		&session.CreatedAt,
+		&session.LastSeenAt,
+		&session.ExpiresAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+func (sr *SessionRepository) GetActiveSessions(userID int) ([]model.Session, error) {
+	rows, err := sr.connection.Query("SELECT id, user_id, device, ip, created_at, last_seen_at, expires_at"+
+		" FROM sessions WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > now()"+
+		" ORDER BY last_seen_at DESC", userID)
+	if err != nil {
+		return []model.Session{}, err
+	}
+	defer rows.Close()
+
+	sessions := []model.Session{}
+	for rows.Next() {
+		var session model.Session
+		err := rows.Scan(
+			&session.ID,
+			&session.UserID,
+			&session.Device,
+			&session.IP,
+			&session.CreatedAt,
+			&session.LastSeenAt,
+			&session.ExpiresAt,
+		)
+		if err != nil {
+			return []model.Session{}, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+// RotateRefreshToken troca o hash do refresh token e registra o uso da sessão, apenas se
+// o hash atual for currentHash e a sessão estiver ativa. O UPDATE condicional garante que,
+// entre requisições concorrentes com o mesmo token, só uma faça a rotação. Retorna nil
+// quando a sessão não foi rotacionada
+func (sr *SessionRepository) RotateRefreshToken(id, currentHash, newHash, ip string) (*model.Session, error) {
+	var session model.Session
+
+	err := sr.connection.QueryRow("UPDATE sessions SET previous_refresh_token_hash = refresh_token_hash,"+
+		" refresh_token_hash = $3, ip = $4, last_seen_at = now()"+
+		" WHERE id = $1 AND refresh_token_hash = $2 AND revoked_at IS NULL AND expires_at > now()"+
+		" RETURNING id, user_id, device, ip, created_at, last_seen_at, expires_at",
+		id, currentHash, newHash, ip).Scan(
+		&session.ID,
+		&session.UserID,
+		&session.Device,
+		&session.IP,
+		&session.CreatedAt,
+		&session.LastSeenAt,
+		&session.ExpiresAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	session.RefreshTokenHash = newHash
+	return &session, nil
+}
+
+// RevokeReusedToken revoga a sessão ativa cujo refresh token anterior à última rotação é
+// previousHash e devolve o usuário dela; 0 quando não há sessão nessa situação
+func (sr *SessionRepository) RevokeReusedToken(id, previousHash string) (int, error) {
+	var userID int
+	err := sr.connection.QueryRow("UPDATE sessions SET revoked_at = now()"+
+		" WHERE id = $1 AND previous_refresh_token_hash = $2 AND previous_refresh_token_hash <> ''"+
+		" AND revoked_at IS NULL RETURNING user_id", id, previousHash).Scan(&userID)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	return userID, err
+}
+
+// RevokeSessions revoga as sessões ativas do usuário; sem ids, revoga todas.
+// Retorna os ids efetivamente revogados.
+func (sr *SessionRepository) RevokeSessions(userID int, ids ...string) ([]string, error) {
+	query := "UPDATE sessions SET revoked_at = now()" +
+		" WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > now()"
+	args := []any{userID}
+
+	if len(ids) > 0 {
+		query += " AND id = ANY($2)"
+		args = append(args, pq.Array(ids))
+	}
+
+	rows, err := sr.connection.Query(query+" RETURNING id", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var revoked []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		revoked = append(revoked, id)
+	}
+
+	return revoked, rows.Err()
+}
+
+// GetRevokedSince lista as sessões revogadas a partir de `since`, usado para
+// reconstruir o RevocationStore no boot
+func (sr *SessionRepository) GetRevokedSince(since time.Time) ([]string, error) {
+	rows, err := sr.connection.Query("SELECT id FROM sessions WHERE revoked_at >= $1", since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"database/sql"
+	"strings"
+
+	"github.com/pytsx/goapi/model"
+)
+
+type SuppressionRepository struct {
+	connection *sql.DB
+}
+
+func NewSuppressionRepository(conn *sql.DB) SuppressionRepository {
+	return SuppressionRepository{
+		connection: conn,
+	}
+}
+
+// Suppress registra o endereço na lista de supressão e marca os usuários com esse email.
+// Devolve os ids dos usuários que passaram a ser marcados nesta chamada
+func (sr *SuppressionRepository) Suppress(event model.EmailEvent) ([]int, error) {
+	tx, err := sr.connection.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	email := strings.ToLower(event.Email)
+
+	_, err = tx.Exec("INSERT INTO email_suppressions (email, reason, provider, detail)"+
+		" VALUES ($1, $2, $3, $4) ON CONFLICT (email) DO UPDATE"+
+		" SET reason = EXCLUDED.reason, provider = EXCLUDED.provider, detail = EXCLUDED.detail, created_at = now()",
+		email, event.Type, event.Provider, event.Detail)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query("UPDATE users SET email_undeliverable = TRUE"+
+		" WHERE lower(email) = $1 AND NOT email_undeliverable RETURNING id", email)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	userIDs := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return userIDs, tx.Commit()
+}
+
+func (sr *SuppressionRepository) IsSuppressed(email string) (bool, error) {
+	var suppressed bool
+
+	err := sr.connection.QueryRow("SELECT EXISTS (SELECT 1 FROM email_suppressions WHERE email = $1)",
+		strings.ToLower(email)).Scan(&suppressed)
+
+	return suppressed, err
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/pytsx/goapi/model"
+)
+
+type TaskRepository struct {
+	connection DBTX
+}
+
+func NewTaskRepository(conn *sql.DB) TaskRepository {
+	return TaskRepository{
+		connection: conn,
+	}
+}
+
+// WithContext vincula os comandos ao contexto da requisição (deadline e cancelamento)
+func (tr TaskRepository) WithContext(ctx context.Context) TaskRepository {
+	return TaskRepository{
+		connection: bindContext(tr.connection, ctx),
+	}
+}
+
+func (tr TaskRepository) WithTx(tx *sql.Tx) TaskRepository {
+	return TaskRepository{
+		connection: tx,
+	}
+}
+
+func (tr *TaskRepository) Create(kind, actor string) (model.Task, error) {
+	row := tr.connection.QueryRow("INSERT INTO tasks (kind, actor) VALUES ($1, $2)"+
+		" RETURNING id, kind, status, progress, result_url, errors, actor, created_at, updated_at, finished_at",
+		kind, actor)
+
+	task, err := scanTask(row)
+	if err != nil {
+		return model.Task{}, err
+	}
+	return *task, nil
+}
+
+func (tr *TaskRepository) GetTask(id int64) (*model.Task, error) {
+	row := tr.connection.QueryRow("SELECT id, kind, status, progress, result_url, errors, actor,"+
+		" created_at, updated_at, finished_at FROM tasks WHERE id = $1", id)
+
+	task, err := scanTask(row)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return task, err
+}
+
+func (tr *TaskRepository) Start(id int64) error {
+	_, err := tr.connection.Exec("UPDATE tasks SET status = 'running', updated_at = now() WHERE id = $1", id)
+	return err
+}
+
+func (tr *TaskRepository) SetProgress(id int64, progress int) error {
+	_, err := tr.connection.Exec("UPDATE tasks SET progress = $2, updated_at = now() WHERE id = $1", id, progress)
+	return err
+}
+
+// Finish encerra a task com o status final; errors lista as falhas, inclusive as parciais
+// de uma task bem-sucedida. Tasks que falham mantêm o progresso alcançado.
+func (tr *TaskRepository) Finish(id int64, status model.TaskStatus, resultURL string, errors []string) error {
+	if errors == nil {
+		errors = []string{}
+	}
+	encoded, err := json.Marshal(errors)
+	if err != nil {
+		return err
+	}
+
+	_, err = tr.connection.Exec("UPDATE tasks SET status = $2, result_url = $3, errors = $4,"+
+		" progress = CASE WHEN $2 = 'succeeded' THEN 100 ELSE progress END, updated_at = now(), finished_at = now()"+
+		" WHERE id = $1", id, status, resultURL, encoded)
+	return err
+}
+
+func scanTask(row rowScanner) (*model.Task, error) {
+	var task model.Task
+	var errors []byte
+
+	err := row.Scan(
+		&task.ID,
+		&task.Kind,
+		&task.Status,
+		&task.Progress,
+		&task.ResultURL,
+		&errors,
+		&task.Actor,
+		&task.CreatedAt,
+		&task.UpdatedAt,
+		&task.FinishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(errors, &task.Errors); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// DBTX é satisfeito tanto por *sql.DB quanto por *sql.Tx, permitindo que o
+// mesmo repositório participe ou não de uma transação
+type DBTX interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+	Prepare(query string) (*sql.Stmt, error)
+}
+
+type contextDBTX interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+}
+
+// boundDBTX executa os comandos sob um contexto fixo: quando o deadline da
+// requisição expira, o driver cancela a consulta em andamento no servidor
+type boundDBTX struct {
+	connection contextDBTX
+	ctx        context.Context
+}
+
+func bindContext(conn DBTX, ctx context.Context) DBTX {
+	if bound, ok := conn.(boundDBTX); ok {
+		return boundDBTX{connection: bound.connection, ctx: ctx}
+	}
+	if withContext, ok := conn.(contextDBTX); ok {
+		return boundDBTX{connection: withContext, ctx: ctx}
+	}
+	return conn
+}
+
+func (b boundDBTX) Exec(query string, args ...any) (sql.Result, error) {
+	return b.connection.ExecContext(b.ctx, query, args...)
+}
+
+func (b boundDBTX) Query(query string, args ...any) (*sql.Rows, error) {
+	return b.connection.QueryContext(b.ctx, query, args...)
+}
+
+func (b boundDBTX) QueryRow(query string, args ...any) *sql.Row {
+	return b.connection.QueryRowContext(b.ctx, query, args...)
+}
+
+func (b boundDBTX) Prepare(query string) (*sql.Stmt, error) {
+	return b.connection.PrepareContext(b.ctx, query)
+}
+
+type Transactor struct {
+	connection *sql.DB
+	ctx        context.Context
+}
+
+func NewTransactor(conn *sql.DB) Transactor {
+	return Transactor{
+		connection: conn,
+		ctx:        context.Background(),
+	}
+}
+
+// WithContext faz as próximas transações serem abortadas quando ctx expirar
+func (t Transactor) WithContext(ctx context.Context) Transactor {
+	return Transactor{
+		connection: t.connection,
+		ctx:        ctx,
+	}
+}
+
+// WithinTx executa fn em uma transação, confirmando apenas se fn não retornar erro
+func (t *Transactor) WithinTx(fn func(tx *sql.Tx) error) error {
+	tx, err := t.connection.BeginTx(t.ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// withoutStatementTimeout roda fn numa conexão dedicada sem o statement_timeout do pool,
+// que interromperia as operações longas; o valor original é restaurado antes de a
+// conexão voltar ao pool
+func withoutStatementTimeout(ctx context.Context, db *sql.DB, fn func(conn *sql.Conn) error) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "SET statement_timeout = 0"); err != nil {
+		return err
+	}
+	defer conn.ExecContext(context.Background(), "RESET statement_timeout")
+
+	return fn(conn)
+}
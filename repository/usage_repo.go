@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// UsageRepository guarda os contadores diários das chaves de API quando não há Redis
+type UsageRepository struct {
+	connection *sql.DB
+}
+
+func NewUsageRepository(conn *sql.DB) UsageRepository {
+	return UsageRepository{
+		connection: conn,
+	}
+}
+
+func (ur UsageRepository) Increment(ctx context.Context, keyID string, day time.Time) (int64, error) {
+	var requests int64
+
+	err := ur.connection.QueryRowContext(ctx, "INSERT INTO api_key_usage (key_id, day, requests) VALUES ($1, $2, 1)"+
+		" ON CONFLICT (key_id, day) DO UPDATE SET requests = api_key_usage.requests + 1"+
+		" RETURNING requests", keyID, day).Scan(&requests)
+
+	return requests, err
+}
+
+func (ur UsageRepository) Usage(ctx context.Context, keyIDs []string, day time.Time) (map[string]int64, error) {
+	rows, err := ur.connection.QueryContext(ctx, "SELECT key_id, requests FROM api_key_usage"+
+		" WHERE key_id = ANY($1) AND day = $2", pq.Array(keyIDs), day)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	usage := make(map[string]int64, len(keyIDs))
+	for rows.Next() {
+		var keyID string
+		var requests int64
+		if err := rows.Scan(&keyID, &requests); err != nil {
+			return nil, err
+		}
+		usage[keyID] = requests
+	}
+
+	return usage, rows.Err()
+}
@@ -1,31 +1,92 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/lib/pq"
 	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/normalize"
 )
 
+// ErrDuplicateEmail indica que já existe um usuário com o mesmo email, sem diferenciar maiúsculas
+var ErrDuplicateEmail = errors.New("email já cadastrado")
+
 type UserRepository struct {
-	connection *sql.DB
+	connection DBTX
+	// pool de origem, para as leituras que precisam de uma conexão dedicada
+	db *sql.DB
 }
 
 func NewUserRepository(conn *sql.DB) UserRepository {
 
 	return UserRepository{
 		connection: conn,
+		db:         conn,
+	}
+}
+
+// WithContext vincula os comandos ao contexto da requisição (deadline e cancelamento)
+func (ur UserRepository) WithContext(ctx context.Context) UserRepository {
+	return UserRepository{
+		connection: bindContext(ur.connection, ctx),
+		db:         ur.db,
+	}
+}
+
+func (ur UserRepository) WithTx(tx *sql.Tx) UserRepository {
+	return UserRepository{
+		connection: tx,
+		db:         ur.db,
 	}
 }
 
-func (ur *UserRepository) GetUsers() ([]model.User, error) {
-	query := "SELECT id, name, email, img_url FROM users"
-	rows, err := ur.connection.Query(query)
+// colunas pelas quais a listagem pode ser ordenada
+var userSortColumns = map[string]string{
+	"id":                   "id",
+	"name":                 "name",
+	"created_at":           "created_at",
+	"profile_completeness": "profile_completeness",
+}
+
+// GetUsers lista uma página de usuários; o id desempata a ordenação para a paginação ser estável
+func (ur *UserRepository) GetUsers(filter model.UserFilter, limit, offset int) ([]model.User, error) {
+	builder := selectFrom("users", "id, name, email, img_url, email_undeliverable, created_at, profile_completeness").
+		WhereIf(filter.Name != "", "name ILIKE ?", "%"+escapeLike(filter.Name)+"%").
+		WhereIf(filter.CreatedAfter != nil, "created_at >= ?", filter.CreatedAfter).
+		WhereIf(filter.CreatedBefore != nil, "created_at < ?", filter.CreatedBefore).
+		WhereIf(filter.MinCompleteness != nil, "profile_completeness >= ?", filter.MinCompleteness).
+		WhereIf(filter.MaxCompleteness != nil, "profile_completeness <= ?", filter.MaxCompleteness)
+
+	field, descending := strings.CutPrefix(filter.Sort, "-")
+	column, ok := userSortColumns[field]
+	if !ok {
+		column, descending = "id", false
+	}
+	order := column
+	if descending {
+		order += " DESC"
+	}
+	builder.OrderBy(order)
+	if column != "id" {
+		builder.OrderBy("id")
+	}
+
+	query, args := builder.Limit(limit).Offset(offset).Build()
+	rows, err := ur.connection.Query(query, args...)
 
 	if err != nil {
 		return []model.User{}, err
 	}
+	defer rows.Close()
 
-	var usersList []model.User
+	usersList := []model.User{}
 	var userObj model.User
 
 	for rows.Next() {
@@ -34,6 +95,9 @@ func (ur *UserRepository) GetUsers() ([]model.User, error) {
 			&userObj.Name,
 			&userObj.Email,
 			&userObj.ImgURL,
+			&userObj.EmailUndeliverable,
+			&userObj.CreatedAt,
+			&userObj.ProfileCompleteness,
 		)
 
 		if err != nil {
@@ -43,32 +107,132 @@ func (ur *UserRepository) GetUsers() ([]model.User, error) {
 		usersList = append(usersList, userObj)
 	}
 
-	rows.Close()
-
+	// o prazo da requisição pode cancelar a consulta no meio da leitura
+	if err := rows.Err(); err != nil {
+		return []model.User{}, err
+	}
 	return usersList, nil
 }
 
-func (ur *UserRepository) CreateUser(user model.User) (int, error) {
-	var id int
+// GetUsersByIDs devolve, ordenados por id, os usuários existentes entre os ids informados
+func (ur *UserRepository) GetUsersByIDs(ids []int) ([]model.User, error) {
+	query := "SELECT id, name, email, img_url, email_undeliverable, created_at, profile_completeness FROM users" +
+		" WHERE id = ANY($1) ORDER BY id"
+	rows, err := ur.connection.Query(query, pq.Array(ids))
+	if err != nil {
+		return []model.User{}, err
+	}
+	defer rows.Close()
 
-	query, err := ur.connection.Prepare("INSERT INTO user " +
-		"(user_name, user_email, user_imgurl)" +
-		" VALUES ($1, $2, $3) RETURNING user_id")
+	usersList := []model.User{}
+	for rows.Next() {
+		var user model.User
+		err := rows.Scan(
+			&user.ID,
+			&user.Name,
+			&user.Email,
+			&user.ImgURL,
+			&user.EmailUndeliverable,
+			&user.CreatedAt,
+			&user.ProfileCompleteness,
+		)
+		if err != nil {
+			return []model.User{}, err
+		}
+
+		usersList = append(usersList, user)
+	}
+
+	return usersList, rows.Err()
+}
+
+// StreamUsers entrega os usuários um a um, sem montar a lista em memória.
+// Lê no máximo maxRows linhas; truncated indica que havia mais registros.
+func (ur *UserRepository) StreamUsers(maxRows int, fn func(model.User) error) (truncated bool, err error) {
+	query := "SELECT id, name, email, img_url, email_undeliverable, created_at, profile_completeness FROM users" +
+		" ORDER BY id LIMIT $1"
+	rows, err := ur.connection.Query(query, maxRows+1)
 	if err != nil {
-		return -1, err
+		return false, err
 	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		if count == maxRows {
+			return true, nil
+		}
 
-	err = query.QueryRow(user.Name, user.Email, user.ImgURL).Scan(&id)
+		var user model.User
+		err := rows.Scan(
+			&user.ID,
+			&user.Name,
+			&user.Email,
+			&user.ImgURL,
+			&user.EmailUndeliverable,
+			&user.CreatedAt,
+			&user.ProfileCompleteness,
+		)
+		if err != nil {
+			return false, err
+		}
+
+		if err := fn(user); err != nil {
+			return false, err
+		}
+		count++
+	}
+
+	return false, rows.Err()
+}
+
+// ExportUsers percorre todos os usuários como StreamUsers, mas numa conexão dedicada sem o
+// statement_timeout do pool, que interromperia a leitura de tabelas grandes
+func (ur *UserRepository) ExportUsers(ctx context.Context, fn func(model.User) error) error {
+	return withoutStatementTimeout(ctx, ur.db, func(conn *sql.Conn) error {
+		dedicated := UserRepository{connection: boundDBTX{connection: conn, ctx: ctx}, db: ur.db}
+		_, err := dedicated.StreamUsers(math.MaxInt32, fn)
+		return err
+	})
+}
+
+// CreateUser devolve o registro como foi gravado, com os valores preenchidos pelo banco
+func (ur *UserRepository) CreateUser(user model.User) (model.User, error) {
+	var created model.User
+
+	query, err := ur.connection.Prepare("INSERT INTO users " +
+		"(name, email, img_url, password_hash, moderation_status, moderation_reason, email_canonical, profile_completeness)" +
+		" VALUES ($1, $2, $3, $4, $5, $6, $7, $8)" +
+		" RETURNING id, name, email, img_url, email_undeliverable, created_at, profile_completeness")
 	if err != nil {
-		return -1, err
+		return model.User{}, err
 	}
+	defer query.Close()
 
-	query.Close()
-	return id, nil
+	err = query.QueryRow(user.Name, user.Email, user.ImgURL, user.PasswordHash,
+		user.ModerationStatus, user.ModerationReason, normalize.CanonicalEmail(user.Email), user.ProfileCompleteness).Scan(
+		&created.ID,
+		&created.Name,
+		&created.Email,
+		&created.ImgURL,
+		&created.EmailUndeliverable,
+		&created.CreatedAt,
+		&created.ProfileCompleteness,
+	)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "users_email_lower_idx" {
+		return model.User{}, ErrDuplicateEmail
+	}
+	if err != nil {
+		return model.User{}, err
+	}
+
+	return created, nil
 }
 
 func (ur *UserRepository) GetUser(id int) (*model.User, error) {
-	query, err := ur.connection.Prepare("SELECT * FROM users WHERE id = $1")
+	query, err := ur.connection.Prepare("SELECT id, name, email, img_url, email_undeliverable, created_at, profile_completeness" +
+		" FROM users WHERE id = $1")
 	if err != nil {
 		return nil, err
 	}
@@ -80,10 +244,13 @@ func (ur *UserRepository) GetUser(id int) (*model.User, error) {
 		&user.Name,
 		&user.Email,
 		&user.ImgURL,
+		&user.EmailUndeliverable,
+		&user.CreatedAt,
+		&user.ProfileCompleteness,
 	)
 
 	if err != nil {
-		if err == sql.ErrConnDone {
+		if err == sql.ErrNoRows {
 			return nil, nil
 		}
 		return nil, err
@@ -93,3 +260,329 @@ func (ur *UserRepository) GetUser(id int) (*model.User, error) {
 
 	return &user, nil
 }
+
+// GetUserByEmail não diferencia maiúsculas, como o índice único users_email_lower_idx
+func (ur *UserRepository) GetUserByEmail(email string) (*model.User, error) {
+	query, err := ur.connection.Prepare("SELECT id, name, email, img_url, password_hash" +
+		" FROM users WHERE lower(email) = lower($1)")
+	if err != nil {
+		return nil, err
+	}
+	defer query.Close()
+
+	var user model.User
+
+	err = query.QueryRow(email).Scan(
+		&user.ID,
+		&user.Name,
+		&user.Email,
+		&user.ImgURL,
+		&user.PasswordHash,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// GetPasswordHash devolve o hash da senha; vazio se o usuário não existe ou não tem senha
+func (ur *UserRepository) GetPasswordHash(id int) (string, error) {
+	var hash string
+	err := ur.connection.QueryRow("SELECT password_hash FROM users WHERE id = $1", id).Scan(&hash)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return hash, err
+}
+
+func (ur *UserRepository) UpdatePassword(id int, hash string) error {
+	_, err := ur.connection.Exec("UPDATE users SET password_hash = $2 WHERE id = $1", id, hash)
+	return err
+}
+
+// UpdateAvatar troca a imagem do usuário por um avatar já aprovado pelos scanners
+func (ur *UserRepository) UpdateAvatar(id int, imgURL string) error {
+	_, err := ur.connection.Exec("UPDATE users SET img_url = $2, avatar_status = 'approved', avatar_reason = ''"+
+		" WHERE id = $1", id, imgURL)
+
+	return err
+}
+
+// GetProfileFacts reúne os dados que compõem o profile_completeness. Retorna nil
+// quando o usuário não existe
+func (ur *UserRepository) GetProfileFacts(id int) (*model.ProfileFacts, error) {
+	var facts model.ProfileFacts
+	err := ur.connection.QueryRow("SELECT u.img_url <> '', NOT u.email_undeliverable, u.password_hash <> '',"+
+		" EXISTS (SELECT 1 FROM user_preferences p WHERE p.user_id = u.id AND p.locale <> '')"+
+		" FROM users u WHERE u.id = $1", id).Scan(
+		&facts.HasAvatar,
+		&facts.EmailDeliverable,
+		&facts.HasPassword,
+		&facts.HasLocale,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &facts, nil
+}
+
+// SetProfileCompleteness só grava quando o valor muda, para não marcar o usuário como
+// alterado na sincronização incremental sem necessidade
+func (ur *UserRepository) SetProfileCompleteness(id, completeness int) error {
+	_, err := ur.connection.Exec("UPDATE users SET profile_completeness = $2"+
+		" WHERE id = $1 AND profile_completeness <> $2", id, completeness)
+	return err
+}
+
+// FindByCanonicalEmail devolve os usuários cujo email normalizado coincide com o informado
+func (ur *UserRepository) FindByCanonicalEmail(email string, limit int) ([]model.DuplicateMatch, error) {
+	return ur.findDuplicates("SELECT id, name, email, img_url, email_undeliverable, created_at, profile_completeness, 1.0"+
+		" FROM users WHERE email_canonical = $1 ORDER BY id LIMIT $2",
+		model.DuplicateByEmail, normalize.CanonicalEmail(email), limit)
+}
+
+// FindSimilarNames usa a similaridade de trigramas (pg_trgm) para achar nomes parecidos.
+// O operador % usa o índice users_name_trgm_idx, ao contrário de similarity() no WHERE, e
+// o seu limiar é definido com set_config local: chame dentro de uma transação (WithTx)
+func (ur *UserRepository) FindSimilarNames(name string, threshold float64, limit int) ([]model.DuplicateMatch, error) {
+	_, err := ur.connection.Exec("SELECT set_config('pg_trgm.similarity_threshold', $1, true)",
+		strconv.FormatFloat(threshold, 'f', -1, 64))
+	if err != nil {
+		return nil, err
+	}
+
+	return ur.findDuplicates("SELECT id, name, email, img_url, email_undeliverable, created_at, profile_completeness, similarity(name, $1)"+
+		" FROM users WHERE name % $1 ORDER BY name <-> $1, id LIMIT $2",
+		model.DuplicateByName, name, limit)
+}
+
+func (ur *UserRepository) findDuplicates(query, reason string, args ...any) ([]model.DuplicateMatch, error) {
+	rows, err := ur.connection.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	matches := []model.DuplicateMatch{}
+	for rows.Next() {
+		match := model.DuplicateMatch{Reason: reason}
+		err := rows.Scan(
+			&match.User.ID,
+			&match.User.Name,
+			&match.User.Email,
+			&match.User.ImgURL,
+			&match.User.EmailUndeliverable,
+			&match.User.CreatedAt,
+			&match.User.ProfileCompleteness,
+			&match.Score,
+		)
+		if err != nil {
+			return nil, err
+		}
+		matches = append(matches, match)
+	}
+
+	return matches, rows.Err()
+}
+
+func (ur *UserRepository) CountUsers() (int64, error) {
+	var count int64
+	err := ur.connection.QueryRow("SELECT count(*) FROM users").Scan(&count)
+	return count, err
+}
+
+// relações que referenciam users (id), pelo nome usado nas estratégias de remoção
+var userRelations = map[string]struct{ table, column string }{
+	"preferences":   {"user_preferences", "user_id"},
+	"login_history": {"login_history", "user_id"},
+	"sessions":      {"sessions", "user_id"},
+	"consents":      {"consents", "user_id"},
+	"invitations":   {"invitation_uses", "user_id"},
+}
+
+// ReferencedError indica que o usuário ainda é referenciado por uma tabela
+// cuja relação não foi tratada antes da remoção
+type ReferencedError struct {
+	Table string
+}
+
+func (e ReferencedError) Error() string {
+	return "usuário referenciado pela tabela " + e.Table
+}
+
+func userRelation(name string) (string, string, error) {
+	relation, ok := userRelations[name]
+	if !ok {
+		return "", "", fmt.Errorf("relação de usuário desconhecida: %s", name)
+	}
+	return relation.table, relation.column, nil
+}
+
+// CountRelated conta os registros da relação que pertencem ao usuário
+func (ur *UserRepository) CountRelated(relation string, id int) (int64, error) {
+	table, column, err := userRelation(relation)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	err = ur.connection.QueryRow("SELECT count(*) FROM "+table+" WHERE "+column+" = $1", id).Scan(&count)
+	return count, err
+}
+
+// DeleteRelated apaga os registros da relação que pertencem ao usuário
+func (ur *UserRepository) DeleteRelated(relation string, id int) (int64, error) {
+	table, column, err := userRelation(relation)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := ur.connection.Exec("DELETE FROM "+table+" WHERE "+column+" = $1", id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// OrphanRelated desvincula os registros da relação do usuário, mantendo-os sem dono
+func (ur *UserRepository) OrphanRelated(relation string, id int) (int64, error) {
+	table, column, err := userRelation(relation)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := ur.connection.Exec("UPDATE "+table+" SET "+column+" = NULL WHERE "+column+" = $1", id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// DeleteUser remove apenas o usuário, deixando uma lápide para a sincronização
+// incremental; as relações devem ser tratadas antes. Retorna false quando o
+// usuário não existe e ReferencedError quando ainda há registros dependentes.
+func (ur *UserRepository) DeleteUser(id int) (bool, error) {
+	result, err := ur.connection.Exec("DELETE FROM users WHERE id = $1", id)
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		return false, ReferencedError{Table: pqErr.Table}
+	}
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil || affected == 0 {
+		return false, err
+	}
+
+	_, err = ur.connection.Exec("INSERT INTO user_tombstones (user_id) VALUES ($1)"+
+		" ON CONFLICT (user_id) DO UPDATE SET deleted_at = clock_timestamp()", id)
+	return err == nil, err
+}
+
+// GetChanges lista criações, atualizações e remoções posteriores ao cursor e
+// mais antigas que settle, na ordem em que aconteceram
+func (ur *UserRepository) GetChanges(after model.ChangeCursor, settle time.Duration, limit int) ([]model.UserChange, error) {
+	// o limite vem do relógio do banco, o mesmo que grava updated_at: o da réplica pode estar adiantado
+	rows, err := ur.connection.Query("SELECT user_id, change, changed_at FROM ("+
+		" SELECT id AS user_id, CASE WHEN created_at > $1 THEN 'created' ELSE 'updated' END AS change,"+
+		" updated_at AS changed_at FROM users"+
+		" UNION ALL"+
+		" SELECT user_id, 'deleted', deleted_at FROM user_tombstones"+
+		") changes WHERE (changed_at, user_id) > ($1, $2) AND changed_at < clock_timestamp() - make_interval(secs => $3)"+
+		" ORDER BY changed_at, user_id LIMIT $4", after.At, after.UserID, settle.Seconds(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []model.UserChange{}
+	for rows.Next() {
+		var change model.UserChange
+		if err := rows.Scan(&change.UserID, &change.Change, &change.At); err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, rows.Err()
+}
+
+// AnonymizeUser substitui os dados pessoais por valores neutros, mantendo o id para
+// as referências, apaga o histórico de logins e a supressão do email original e limpa
+// IPs e dispositivos de sessões, consentimentos e da auditoria das ações do usuário.
+// Deve rodar numa transação (WithTx). Retorna false quando o usuário não existe.
+func (ur *UserRepository) AnonymizeUser(id int) (bool, error) {
+	var previousEmail string
+	err := ur.connection.QueryRow("SELECT email FROM users WHERE id = $1 FOR UPDATE", id).Scan(&previousEmail)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	email := "anonimo+" + strconv.Itoa(id) + "@invalid"
+	_, err = ur.connection.Exec("UPDATE users SET name = 'Usuário anônimo', email = $2,"+
+		" email_canonical = $2, img_url = '', password_hash = '' WHERE id = $1", id, email)
+	if err != nil {
+		return false, err
+	}
+
+	// rastros do usuário nas outras tabelas: IPs, dispositivos e o email original
+	scrub := []struct {
+		query string
+		arg   any
+	}{
+		{"DELETE FROM login_history WHERE user_id = $1", id},
+		{"UPDATE sessions SET ip = '', device = '' WHERE user_id = $1", id},
+		{"UPDATE consents SET ip = '' WHERE user_id = $1", id},
+		{"UPDATE audit_log SET ip = '', country = '' WHERE actor = $1", "user:" + strconv.Itoa(id)},
+		{"DELETE FROM email_suppressions WHERE email = lower($1)", previousEmail},
+	}
+	for _, step := range scrub {
+		if _, err := ur.connection.Exec(step.query, step.arg); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// escapeLike faz % e _ do termo buscado serem tratados como texto no LIKE
+func escapeLike(term string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(term)
+}
+
+// GetRelated conta, numa única consulta, os registros ligados ao usuário.
+// Retorna nil quando o usuário não existe.
+func (ur *UserRepository) GetRelated(id int) (*model.UserRelated, error) {
+	related := model.UserRelated{UserID: id}
+
+	err := ur.connection.QueryRow("SELECT"+
+		" (SELECT count(*) FROM sessions WHERE user_id = u.id AND revoked_at IS NULL AND expires_at > now()),"+
+		" (SELECT count(*) FROM consents WHERE user_id = u.id),"+
+		" (SELECT max(created_at) FROM login_history WHERE user_id = u.id)"+
+		" FROM users u WHERE u.id = $1", id).Scan(
+		&related.ActiveSessions,
+		&related.AcceptedConsents,
+		&related.LastLoginAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &related, nil
+}
@@ -0,0 +1,104 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+const clamChunkSize = 64 * 1024
+
+// ClamAVScanner envia o conteúdo ao clamd pelo comando INSTREAM
+type ClamAVScanner struct {
+	addr    string
+	timeout time.Duration
+}
+
+func NewClamAVScanner(addr string) ClamAVScanner {
+	return ClamAVScanner{
+		addr:    addr,
+		timeout: 30 * time.Second,
+	}
+}
+
+func (s ClamAVScanner) Scan(ctx context.Context, content []byte) (Result, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return Result{}, err
+	}
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(s.timeout))
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return Result{}, err
+	}
+
+	// cada bloco é precedido pelo tamanho (uint32 big-endian); um bloco vazio encerra o envio
+	size := make([]byte, 4)
+	for start := 0; start < len(content); start += clamChunkSize {
+		end := min(start+clamChunkSize, len(content))
+
+		binary.BigEndian.PutUint32(size, uint32(end-start))
+		if _, err := conn.Write(size); err != nil {
+			return Result{}, err
+		}
+		if _, err := conn.Write(content[start:end]); err != nil {
+			return Result{}, err
+		}
+	}
+
+	binary.BigEndian.PutUint32(size, 0)
+	if _, err := conn.Write(size); err != nil {
+		return Result{}, err
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString('\x00')
+	if err != nil {
+		return Result{}, err
+	}
+	reply = strings.TrimSuffix(reply, "\x00")
+
+	// respostas: "stream: OK" ou "stream: <assinatura> FOUND"
+	switch {
+	case strings.HasSuffix(reply, "OK"):
+		return Result{Clean: true}, nil
+	case strings.HasSuffix(reply, "FOUND"):
+		signature := strings.TrimSuffix(strings.TrimPrefix(reply, "stream: "), " FOUND")
+		return Result{Clean: false, Reason: "malware: " + signature}, nil
+	default:
+		return Result{}, fmt.Errorf("resposta inesperada do clamd: %s", reply)
+	}
+}
+
+func (s ClamAVScanner) Ping(ctx context.Context) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if _, err := conn.Write([]byte("zPING\x00")); err != nil {
+		return err
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString('\x00')
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSuffix(reply, "\x00") != "PONG" {
+		return fmt.Errorf("resposta inesperada do clamd: %s", reply)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pytsx/goapi/httpclient"
+)
+
+// NSFWScanner envia a imagem a um modelo de classificação que responde {"nsfw_score": 0.0-1.0}
+type NSFWScanner struct {
+	endpoint  string
+	apiKey    string
+	threshold float64
+	client    *httpclient.Client
+}
+
+func NewNSFWScanner(endpoint, apiKey string, threshold float64, outbound httpclient.Config) NSFWScanner {
+	return NSFWScanner{
+		endpoint:  endpoint,
+		apiKey:    apiKey,
+		threshold: threshold,
+		client:    httpclient.New("nsfw", outbound.WithTimeout(10*time.Second)),
+	}
+}
+
+func (s NSFWScanner) Scan(ctx context.Context, content []byte) (Result, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(content))
+	if err != nil {
+		return Result{}, err
+	}
+	req.Header.Set("Content-Type", http.DetectContentType(content))
+	if s.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return Result{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return Result{}, fmt.Errorf("classificador NSFW retornou %d", resp.StatusCode)
+	}
+
+	var body struct {
+		NSFWScore float64 `json:"nsfw_score"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return Result{}, err
+	}
+
+	if body.NSFWScore >= s.threshold {
+		return Result{Clean: false, Reason: fmt.Sprintf("conteúdo impróprio (score %.2f)", body.NSFWScore)}, nil
+	}
+
+	return Result{Clean: true}, nil
+}
@@ -0,0 +1,29 @@
+package scanner
+
+import "context"
+
+type Result struct {
+	Clean  bool
+	Reason string
+}
+
+type Scanner interface {
+	Scan(ctx context.Context, content []byte) (Result, error)
+}
+
+// Chain executa os scanners em ordem e para no primeiro que reprovar o conteúdo
+type Chain []Scanner
+
+func (c Chain) Scan(ctx context.Context, content []byte) (Result, error) {
+	for _, scanner := range c {
+		result, err := scanner.Scan(ctx, content)
+		if err != nil {
+			return Result{}, err
+		}
+		if !result.Clean {
+			return result, nil
+		}
+	}
+
+	return Result{Clean: true}, nil
+}
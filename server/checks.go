@@ -0,0 +1,189 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/pytsx/goapi/config"
+	"github.com/pytsx/goapi/db"
+	"github.com/pytsx/goapi/health"
+	"github.com/pytsx/goapi/mailer"
+	"github.com/pytsx/goapi/presence"
+	"github.com/pytsx/goapi/scanner"
+	"github.com/pytsx/goapi/storage"
+)
+
+// dependencyChecks lista as dependências obrigatórias; as opcionais estão em capabilities
+func dependencyChecks(cfg config.Config, conn *sql.DB, stores map[string]storage.LocalStore) []health.Check {
+	checks := []health.Check{
+		{
+			Name: "postgres",
+			Hint: "confira se o banco em " + db.Address() + " está no ar e aceitando conexões",
+			Run:  conn.PingContext,
+		},
+	}
+
+	for _, name := range storeNames(stores) {
+		store := stores[name]
+		checks = append(checks, health.Check{
+			Name: "blob store (" + name + ")",
+			Hint: "confira se o diretório " + store.Dir() + " existe e tem permissão de escrita",
+			Run:  store.Check,
+		})
+	}
+
+	return checks
+}
+
+// capabilities descreve as dependências opcionais. As configuradas são verificadas no boot e
+// no /readyz; as ausentes aparecem como desativadas e as fora do ar como degradadas, e
+// nenhum dos dois casos afeta o boot ou a prontidão
+func capabilities(cfg config.Config) []health.Capability {
+	smtp := health.Capability{
+		Name:     "smtp",
+		Enabled:  cfg.SMTPAddr != "",
+		Features: []string{"envio de emails (boas-vindas, alertas de login, troca de senha)"},
+		Fallback: "os emails são apenas registrados no log",
+	}
+	if smtp.Enabled {
+		smtpMailer := mailer.NewSMTPMailer(cfg.SMTPAddr, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom)
+		smtp.Check = health.Check{
+			Hint: "confira SMTP_ADDR (" + cfg.SMTPAddr + ") ou deixe vazio para apenas registrar os emails no log",
+			// o handshake SMTP costuma ser mais lento que as demais verificações
+			Timeout: 5 * time.Second,
+			Run:     smtpMailer.Ping,
+		}
+	}
+
+	clamav := health.Capability{
+		Name:     "clamav",
+		Enabled:  cfg.ClamAVAddr != "",
+		Features: []string{"antivírus no upload de avatar"},
+		Fallback: "avatares não passam pelo antivírus",
+	}
+	if clamav.Enabled {
+		clamav.Check = health.Check{
+			Hint: "confira se o clamd escuta em CLAMAV_ADDR (" + cfg.ClamAVAddr + ")",
+			Run:  scanner.NewClamAVScanner(cfg.ClamAVAddr).Ping,
+		}
+	}
+
+	redis := health.Capability{
+		Name:     "redis",
+		Enabled:  cfg.RedisAddr != "",
+		Features: []string{"presença compartilhada entre instâncias", "contadores da cota diária"},
+		Fallback: "presença em memória, por instância, e contadores de cota no Postgres",
+	}
+	if redis.Enabled {
+		redis.Check = health.Check{
+			Hint: "confira REDIS_ADDR (" + cfg.RedisAddr + ") ou deixe vazio para manter a presença em memória",
+			Run:  presence.NewRedisTracker(newRedisClient(cfg), cfg.PresenceTTL).Ping,
+		}
+	}
+
+	// integrações HTTP sem verificação própria: o circuit breaker do httpclient cuida das falhas
+	return []health.Capability{
+		smtp,
+		clamav,
+		redis,
+		{
+			Name:     "nsfw",
+			Enabled:  cfg.NSFWAPIURL != "",
+			Features: []string{"classificação de conteúdo impróprio no upload de avatar"},
+			Fallback: "avatares não passam pelo classificador",
+		},
+		{
+			Name:     "moderation api",
+			Enabled:  cfg.ModerationAPIURL != "",
+			Features: []string{"moderação externa dos nomes no cadastro"},
+			Fallback: "apenas as listas de palavras de MODERATION_REJECTED_WORDS e MODERATION_FLAGGED_WORDS",
+		},
+		{
+			Name:     "captcha",
+			Enabled:  cfg.CaptchaProvider != "",
+			Features: []string{"CAPTCHA no cadastro público"},
+			Fallback: "o cadastro público não exige CAPTCHA",
+		},
+		{
+			Name:     "geoip",
+			Enabled:  cfg.GeoIPDBPath != "",
+			Features: []string{"bloqueio por país", "localização no histórico de logins"},
+			Fallback: "sem bloqueio por país e logins sem localização",
+		},
+	}
+}
+
+// readinessChecks só fazem sentido depois do boot: as migrações ainda não foram
+// aplicadas quando as dependências são verificadas
+func readinessChecks(cfg config.Config, conn *sql.DB, stores map[string]storage.LocalStore) []health.Check {
+	checks := []health.Check{
+		{
+			Name: "migrations",
+			Hint: "outra versão da aplicação mudou o schema ou as migrações falharam; confira schema_migrations",
+			Run: func(ctx context.Context) error {
+				pending, err := db.PendingMigrations(ctx, conn)
+				if err != nil {
+					return err
+				}
+				if len(pending) > 0 {
+					return errors.New("migrações pendentes: " + strings.Join(pending, ", "))
+				}
+				return nil
+			},
+		},
+		{
+			Name: "schema",
+			Hint: "colunas ou índices usados pela aplicação foram removidos; compare com db/migrations",
+			Run: func(ctx context.Context) error {
+				drift, err := db.CheckSchema(ctx, conn)
+				if err != nil {
+					return err
+				}
+				if drift.Drifted() {
+					return errors.New(strings.Join(append(drift.MissingColumns, drift.MissingIndexes...), ", ") + " ausentes")
+				}
+				return nil
+			},
+		},
+	}
+
+	minFree := uint64(cfg.HealthMinFreeDiskMB) << 20
+	for _, name := range storeNames(stores) {
+		checks = append(checks, health.Check{
+			Name: "disk (" + name + ")",
+			Hint: "libere espaço no volume de " + stores[name].Dir() + " ou ajuste HEALTH_MIN_FREE_DISK_MB",
+			Run:  health.DiskSpace(stores[name].Dir(), minFree),
+		})
+	}
+
+	return checks
+}
+
+func storeNames(stores map[string]storage.LocalStore) []string {
+	names := make([]string, 0, len(stores))
+	for name := range stores {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func newRedisClient(cfg config.Config) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:     cfg.RedisAddr,
+		Password: cfg.RedisPassword,
+		DB:       cfg.RedisDB,
+	})
+}
+
+// runDependencyChecks roda as verificações registradas até aqui com novas tentativas, antes de subir
+func runDependencyChecks(cfg config.Config, checks []health.Check) health.Report {
+	return health.RunStartupChecks(context.Background(), checks,
+		cfg.StartupCheckAttempts, cfg.StartupCheckBackoff, cfg.StartupCheckTimeout)
+}
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"github.com/pytsx/goapi/middleware"
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/quota"
+	"github.com/pytsx/goapi/usecase"
+)
+
+// runtimeSettings liga o documento de configuração aos componentes ajustáveis em execução
+type runtimeSettings struct {
+	maintenance *middleware.MaintenanceMode
+	limits      *quota.Limits
+	invitations usecase.InvitationUsecase
+}
+
+func (r runtimeSettings) Snapshot() model.RuntimeConfig {
+	return model.RuntimeConfig{
+		Flags: model.RuntimeFlags{
+			Maintenance: r.maintenance.Enabled(),
+			InviteOnly:  r.invitations.Required(),
+		},
+		Quotas: model.RuntimeQuotas{
+			MaxUsers:      r.limits.MaxUsers(),
+			DailyRequests: r.limits.DailyRequests(),
+		},
+	}
+}
+
+func (r runtimeSettings) Apply(config model.RuntimeConfig) {
+	r.maintenance.Set(config.Flags.Maintenance)
+	r.limits.Set(config.Quotas.MaxUsers, config.Quotas.DailyRequests)
+	r.invitations.SetRequired(config.Flags.InviteOnly)
+}
@@ -0,0 +1,543 @@
+package server
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pytsx/goapi/auth"
+	"github.com/pytsx/goapi/captcha"
+	"github.com/pytsx/goapi/chaos"
+	"github.com/pytsx/goapi/config"
+	"github.com/pytsx/goapi/controller"
+	"github.com/pytsx/goapi/db"
+	"github.com/pytsx/goapi/events"
+	"github.com/pytsx/goapi/geoip"
+	"github.com/pytsx/goapi/health"
+	"github.com/pytsx/goapi/httpclient"
+	"github.com/pytsx/goapi/jobs"
+	"github.com/pytsx/goapi/mailer"
+	"github.com/pytsx/goapi/metrics"
+	"github.com/pytsx/goapi/middleware"
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/moderation"
+	"github.com/pytsx/goapi/normalize"
+	"github.com/pytsx/goapi/presence"
+	"github.com/pytsx/goapi/quota"
+	"github.com/pytsx/goapi/repository"
+	"github.com/pytsx/goapi/scanner"
+	"github.com/pytsx/goapi/storage"
+	"github.com/pytsx/goapi/usecase"
+)
+
+// Server monta a API a partir da configuração. Quem importa o pacote pode
+// acrescentar middlewares, assinantes de eventos e rotas sem alterar o main.go:
+//
+//	srv := server.New(config.Load())
+//	srv.Use(myMiddleware)
+//	srv.OnEvent(events.SuspiciousLogin, notifySlack)
+//	srv.Routes(func(r gin.IRouter) { r.GET("/custom", handler) })
+//	srv.Check(health.Check{Name: "audit sink", Run: sink.Ping})
+//	srv.Capability(health.Capability{Name: "broker", Enabled: brokerURL != "",
+//		Features: []string{"eventos para outros serviços"}, Fallback: "eventos só no processo",
+//		Check: health.Check{Run: broker.Ping}})
+//	log.Fatal(srv.Run())
+type Server struct {
+	cfg         config.Config
+	dispatcher  *events.Dispatcher
+	middlewares []gin.HandlerFunc
+	routes      []func(r gin.IRouter)
+	checks      []health.Check
+	// dependências opcionais de quem importa o pacote (broker, índice de busca, etc.)
+	capabilities []health.Capability
+}
+
+func New(cfg config.Config) *Server {
+	return &Server{
+		cfg:        cfg,
+		dispatcher: events.NewDispatcher(),
+	}
+}
+
+// Use registra middlewares aplicados a todas as rotas
+func (s *Server) Use(middlewares ...gin.HandlerFunc) {
+	s.middlewares = append(s.middlewares, middlewares...)
+}
+
+// OnEvent inscreve um handler em um evento de domínio (ex.: events.SuspiciousLogin)
+func (s *Server) OnEvent(name string, handler events.Handler) {
+	s.dispatcher.Subscribe(name, handler)
+}
+
+// Routes registra rotas extras, montadas depois das rotas da API
+func (s *Server) Routes(register func(r gin.IRouter)) {
+	s.routes = append(s.routes, register)
+}
+
+// Check registra uma verificação executada no boot e a cada chamada do /readyz
+func (s *Server) Check(checks ...health.Check) {
+	s.checks = append(s.checks, checks...)
+}
+
+// Capability registra uma dependência opcional; desativada ou fora do ar, aparece no /readyz sem afetar a prontidão
+func (s *Server) Capability(capabilities ...health.Capability) {
+	s.capabilities = append(s.capabilities, capabilities...)
+}
+
+// Run conecta as dependências, monta as rotas e atende as requisições até que um listener
+// falhe ou o processo receba SIGINT/SIGTERM
+func (s *Server) Run() error {
+	cfg := s.cfg
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	engine := gin.Default()
+	if err := engine.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		return err
+	}
+	engine.Use(middleware.RequestMetrics(cfg.MetricsExemplars))
+	engine.Use(middleware.TraceContext())
+
+	ipFilter, err := middleware.NewIPFilter(cfg.IPRulesFile)
+	if err != nil {
+		return err
+	}
+	ipFilter.Watch(cfg.IPRulesReloadInterval)
+
+	engine.Use(ipFilter.Global())
+	engine.Use(middleware.ServiceAccounts(cfg.MTLSServiceAccounts))
+
+	// chaves administrativas continuam passando para que seja possível desligar a manutenção
+	maintenance := middleware.NewMaintenanceMode(cfg.AdminAPIKeys, "/healthz", "/readyz")
+	engine.Use(maintenance.Handler())
+
+	if cfg.GeoIPDBPath != "" {
+		geoResolver, err := geoip.NewMaxMindResolver(cfg.GeoIPDBPath)
+		if err != nil {
+			return err
+		}
+		defer geoResolver.Close()
+
+		engine.Use(middleware.GeoIP(geoResolver, cfg.GeoIPBlockedCountries))
+	}
+
+	var dbDecorators []func(driver.Connector) driver.Connector
+	var injector *chaos.Injector
+	if cfg.ChaosActive() {
+		log.Printf("modo de caos ativo no ambiente %s", cfg.Environment)
+
+		injector = chaos.NewInjector(chaos.Config{
+			Latency:       cfg.ChaosLatency,
+			LatencyRate:   cfg.ChaosLatencyRate,
+			HTTPErrorRate: cfg.ChaosHTTPErrorRate,
+			DBErrorRate:   cfg.ChaosDBErrorRate,
+			DBDropRate:    cfg.ChaosDBDropRate,
+		})
+		engine.Use(middleware.Chaos(injector))
+		dbDecorators = append(dbDecorators, injector.WrapConnector)
+	}
+
+	engine.Use(middleware.DBDeadline(cfg.DBReadTimeout, cfg.DBWriteTimeout))
+
+	// middlewares registrados por extensões rodam depois dos globais e antes de todas as rotas
+	engine.Use(s.middlewares...)
+
+	dbConnection, err := db.ConnectDB(cfg.DBStatementTimeout, dbDecorators...)
+	if err != nil {
+		return err
+	}
+
+	avatarStore, err := storage.NewLocalStore(cfg.AvatarDir, cfg.AvatarBaseURL)
+	if err != nil {
+		return err
+	}
+	quarantineStore, err := storage.NewLocalStore(cfg.AvatarQuarantineDir, "")
+	if err != nil {
+		return err
+	}
+	exportStore, err := storage.NewLocalStore(cfg.ExportDir, "/admin/exports")
+	if err != nil {
+		return err
+	}
+
+	stores := map[string]storage.LocalStore{
+		"avatars":    avatarStore,
+		"quarantine": quarantineStore,
+		"exports":    exportStore,
+	}
+
+	// valida todas as dependências antes de subir, reportando todas as falhas de uma vez
+	checks := health.NewRegistry(cfg.HealthCheckTimeout)
+	checks.Register(dependencyChecks(cfg, dbConnection, stores)...)
+	checks.Register(s.checks...)
+	checks.RegisterCapability(capabilities(cfg)...)
+	checks.RegisterCapability(s.capabilities...)
+	report := runDependencyChecks(cfg, checks.Checks())
+	log.Print(report)
+	for _, capability := range checks.Disabled() {
+		log.Printf("%s não configurado: %s", capability.Name, capability.Fallback)
+	}
+	// opcionais configuradas mas fora do ar: sobe degradado e continua usando a dependência,
+	// que se reconecta sozinha (o Redis, por exemplo); o fallback é só para a não configurada
+	for _, name := range report.Degraded() {
+		log.Printf("%s indisponível, subindo degradado", name)
+	}
+	if report.Failed() {
+		return errors.New("dependências indisponíveis, veja o relatório acima")
+	}
+
+	if err := db.Migrate(dbConnection); err != nil {
+		return err
+	}
+
+	// confere se o schema tem o que os repositórios usam antes de aceitar tráfego
+	drift, err := db.CheckSchema(context.Background(), dbConnection)
+	if err != nil {
+		return err
+	}
+	log.Print(drift)
+	if drift.Drifted() && cfg.SchemaDriftAction != "warn" {
+		return errors.New("o schema do banco diverge do esperado, veja o relatório acima (SCHEMA_DRIFT_ACTION=warn apenas avisa)")
+	}
+	checks.Register(readinessChecks(cfg, dbConnection, stores)...)
+	healthController := controller.NewHealthController(checks, cfg.HealthCacheTTL)
+
+	dispatcher := s.dispatcher
+
+	// base das integrações externas; cada uma define o próprio prazo
+	outbound := httpclient.Config{
+		Retries:          cfg.OutboundRetries,
+		Backoff:          cfg.OutboundBackoff,
+		BreakerThreshold: cfg.OutboundBreakerThreshold,
+		BreakerCooldown:  cfg.OutboundBreakerCooldown,
+	}
+
+	suppressionRepo := repository.NewSuppressionRepository(dbConnection)
+	emailEventUsecase := usecase.NewEmailEventUsecase(suppressionRepo, dispatcher)
+	emailWebhookController := controller.NewEmailWebhookController(emailEventUsecase, cfg.EmailWebhookSecret, outbound)
+
+	var mail mailer.Mailer = mailer.LogMailer{}
+	if cfg.SMTPAddr != "" {
+		mail = mailer.NewSMTPMailer(cfg.SMTPAddr, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom)
+	}
+	mail = mailer.NewSuppressingMailer(mail, &suppressionRepo)
+
+	keySet, err := auth.NewKeySet(cfg.JWTKeysDir, cfg.JWTTokenTTL)
+	if err != nil {
+		return err
+	}
+	keyController := controller.NewKeyController(keySet)
+
+	moderator := moderation.Chain{
+		moderation.NewWordlistModerator(cfg.ModerationRejectedWords, cfg.ModerationFlaggedWords),
+	}
+	if cfg.ModerationAPIURL != "" {
+		moderator = append(moderator, moderation.NewAPIModerator(cfg.ModerationAPIURL, cfg.ModerationAPIKey, outbound))
+	}
+
+	transactor := repository.NewTransactor(dbConnection)
+	auditRepo := repository.NewAuditRepository(dbConnection)
+	jobRepo := repository.NewJobRepository(dbConnection)
+	worker := jobs.NewWorker(jobRepo, cfg.JobsQueues, cfg.JobsPollInterval, cfg.JobsRetryBackoff, cfg.JobsLease)
+
+	normalizer, err := normalize.NewPipeline(cfg.NormalizeRules)
+	if err != nil {
+		return err
+	}
+
+	// sem Redis, a presença fica em memória, os contadores de cota no Postgres e as
+	// revogações de sessão só chegam às outras instâncias quando elas reiniciam
+	var tracker presence.Tracker = presence.NewMemoryTracker(cfg.PresenceTTL)
+	var counter quota.Counter = repository.NewUsageRepository(dbConnection)
+	var revocations auth.RevocationStore = auth.NewMemoryRevocationStore()
+	if cfg.RedisAddr != "" {
+		redisClient := newRedisClient(cfg)
+		defer redisClient.Close()
+
+		tracker = presence.NewRedisTracker(redisClient, cfg.PresenceTTL)
+		counter = quota.NewRedisCounter(redisClient)
+		revocations = auth.NewRedisRevocationStore(redisClient)
+	}
+
+	// limites ajustáveis em tempo de execução pela importação de configuração
+	limits := quota.NewLimits(cfg.QuotaMaxUsers, cfg.QuotaDailyRequests)
+	engine.Use(middleware.DailyQuota(counter, cfg.TrustedAPIKeys, limits))
+
+	invitationRepo := repository.NewInvitationRepository(dbConnection)
+	invitationUsecase := usecase.NewInvitationUsecase(invitationRepo, auditRepo, transactor, cfg.InviteLinkBase, cfg.InviteOnly)
+	invitationController := controller.NewInvitationController(invitationUsecase)
+
+	userRepo := repository.NewUserRepository(dbConnection)
+	userUsecase := usecase.NewUserUsecase(userRepo, auditRepo, jobRepo, transactor, moderator, normalizer,
+		tracker, dispatcher, limits, invitationUsecase, []storage.BlobStore{avatarStore, quarantineStore})
+	userController := controller.NewUserController(userUsecase, cfg.UsersStreamMaxRows)
+
+	presenceUsecase := usecase.NewPresenceUsecase(tracker, userRepo)
+	presenceController := controller.NewPresenceController(presenceUsecase)
+
+	usageUsecase := usecase.NewUsageUsecase(counter, userRepo, cfg.TrustedAPIKeys, limits)
+	usageController := controller.NewUsageController(usageUsecase)
+
+	configRepo := repository.NewRuntimeConfigRepository(dbConnection)
+	configUsecase := usecase.NewConfigUsecase(configRepo, auditRepo, transactor, runtimeSettings{
+		maintenance: maintenance,
+		limits:      limits,
+		invitations: invitationUsecase,
+	})
+	configController := controller.NewConfigController(configUsecase)
+	restored, err := configUsecase.Restore()
+	if err != nil {
+		return err
+	}
+	if restored {
+		log.Print("configuração de execução restaurada da última revisão; ela prevalece sobre as variáveis de ambiente")
+	}
+	configUsecase.Watch(cfg.RuntimeConfigPollInterval)
+
+	sessionRepo := repository.NewSessionRepository(dbConnection)
+	sessionUsecase := usecase.NewSessionUsecase(sessionRepo, revocations, cfg.JWTTokenTTL)
+	sessionController := controller.NewSessionController(sessionUsecase)
+	if err := sessionUsecase.LoadRevocations(); err != nil {
+		return err
+	}
+
+	passwordUsecase := usecase.NewPasswordUsecase(userRepo, auditRepo, sessionRepo, transactor, sessionUsecase,
+		dispatcher, cfg.ReauthWindow)
+	passwordController := controller.NewPasswordController(passwordUsecase)
+
+	loginRepo := repository.NewLoginRepository(dbConnection)
+	authUsecase := usecase.NewAuthUsecase(userRepo, loginRepo, sessionRepo, keySet, revocations,
+		cfg.JWTTokenTTL, cfg.RefreshTokenTTL, dispatcher)
+	authController := controller.NewAuthController(authUsecase)
+
+	preferencesRepo := repository.NewPreferencesRepository(dbConnection)
+	preferencesUsecase := usecase.NewPreferencesUsecase(preferencesRepo, dispatcher)
+	preferencesController := controller.NewPreferencesController(preferencesUsecase)
+
+	consentRepo := repository.NewConsentRepository(dbConnection)
+	consentUsecase := usecase.NewConsentUsecase(consentRepo, cfg.ConsentPolicies)
+	consentController := controller.NewConsentController(consentUsecase)
+
+	moderationRepo := repository.NewModerationRepository(dbConnection)
+	moderationUsecase := usecase.NewModerationUsecase(moderationRepo, userRepo)
+	moderationController := controller.NewModerationController(moderationUsecase)
+
+	avatarScanner := scanner.Chain{}
+	if cfg.ClamAVAddr != "" {
+		avatarScanner = append(avatarScanner, scanner.NewClamAVScanner(cfg.ClamAVAddr))
+	}
+	if cfg.NSFWAPIURL != "" {
+		avatarScanner = append(avatarScanner, scanner.NewNSFWScanner(cfg.NSFWAPIURL, cfg.NSFWAPIKey, cfg.NSFWThreshold, outbound))
+	}
+	avatarUsecase := usecase.NewAvatarUsecase(userRepo, moderationRepo, avatarStore, quarantineStore, avatarScanner,
+		dispatcher)
+	avatarController := controller.NewAvatarController(avatarUsecase, int64(cfg.AvatarMaxBytes))
+
+	notificationUsecase := usecase.NewNotificationUsecase(userRepo, preferencesRepo, mail)
+	dispatcher.Subscribe(events.SuspiciousLogin, notificationUsecase.NotifySuspiciousLogin)
+	dispatcher.Subscribe(events.PasswordChanged, notificationUsecase.NotifyPasswordChanged)
+	worker.Handle(model.JobWelcomeEmail, notificationUsecase.SendWelcomeEmail)
+
+	profileUsecase := usecase.NewProfileUsecase(userRepo)
+	dispatcher.Subscribe(events.UserCreated, profileUsecase.HandleEvent)
+	dispatcher.Subscribe(events.PasswordChanged, profileUsecase.HandleEvent)
+	dispatcher.Subscribe(events.ProfileUpdated, profileUsecase.HandleEvent)
+
+	// operações longas respondem 202 com uma task acompanhada em /tasks/:id
+	taskRepo := repository.NewTaskRepository(dbConnection)
+	taskUsecase := usecase.NewTaskUsecase(taskRepo, jobRepo, transactor)
+	userTaskUsecase := usecase.NewUserTaskUsecase(userUsecase, userRepo, sessionUsecase, taskUsecase, exportStore)
+	taskController := controller.NewTaskController(taskUsecase, userTaskUsecase)
+	worker.Handle(model.TaskUserExport, taskUsecase.Handler(userTaskUsecase.Export))
+	worker.Handle(model.TaskUserImport, taskUsecase.Handler(userTaskUsecase.Import))
+	worker.Handle(model.TaskUserAnonymize, taskUsecase.Handler(userTaskUsecase.Anonymize))
+
+	maintenanceRepo := repository.NewMaintenanceRepository(dbConnection)
+	maintenanceUsecase := usecase.NewMaintenanceUsecase(taskUsecase, maintenanceRepo)
+	maintenanceController := controller.NewMaintenanceController(maintenanceUsecase, configUsecase, maintenance)
+	worker.Handle(model.TaskReindex, taskUsecase.Handler(maintenanceUsecase.Reindex))
+
+	jobUsecase := usecase.NewJobUsecase(jobRepo, auditRepo)
+	jobController := controller.NewJobController(jobUsecase)
+	worker.Start(ctx)
+
+	engine.GET("/metrics", ipFilter.Group("metrics"), gin.WrapH(metrics.Handler()))
+	engine.GET("/healthz", healthController.Live)
+	engine.GET("/readyz", ipFilter.Group("health"), healthController.Ready)
+
+	engine.GET("/ping", func(ctx *gin.Context) {
+		ctx.JSON(200, gin.H{
+			"message": "pong",
+		})
+	})
+
+	// cadastro público: protegido por CAPTCHA quando um provedor está configurado
+	var requireCaptcha gin.HandlerFunc = middleware.Skip
+	if cfg.CaptchaProvider != "" {
+		verifier, err := captcha.NewVerifier(cfg.CaptchaProvider, cfg.CaptchaSecret, outbound)
+		if err != nil {
+			return err
+		}
+		requireCaptcha = middleware.Captcha(verifier, cfg.TrustedAPIKeys)
+	}
+
+	engine.Static(cfg.AvatarBaseURL, avatarStore.Dir())
+
+	// limita a concorrência das rotas caras; grupos sem limite configurado não são restringidos
+	bulkhead := func(name string, match func(ctx *gin.Context) bool) gin.HandlerFunc {
+		limit := cfg.Bulkheads[name]
+		if limit <= 0 {
+			return middleware.Skip
+		}
+		return middleware.NewBulkhead(name, limit, cfg.BulkheadQueueTimeout).Handler(match)
+	}
+	// só as exportações de admins ocupam as vagas: anônimos com paginate=false são recusados
+	// pelo controller e não podem esgotá-las; por isso vem depois de identifyAdmin
+	exportsBulkhead := bulkhead("exports", func(ctx *gin.Context) bool {
+		return middleware.IsAdmin(ctx) && ctx.Query("paginate") == "false"
+	})
+
+	// usuários com termos pendentes só acessam consentimentos, sessões e autenticação;
+	// nas rotas abertas a anônimos, o bloqueio vale a partir do momento em que há um token
+	var requireConsents gin.HandlerFunc = middleware.Skip
+	if cfg.ConsentEnforce {
+		requireConsents = middleware.RequireConsents(&consentUsecase)
+	}
+
+	// rotas públicas que mascaram campos conforme o chamador (admin, dono ou anônimo)
+	identifyAdmin := middleware.IdentifyAdmin(cfg.AdminAPIKeys)
+	identifyUser := middleware.IdentifyUser(keySet, revocations)
+	engine.GET("/users", identifyAdmin, exportsBulkhead, identifyUser, requireConsents, userController.GetUsers)
+	engine.GET("/users/:id/related", identifyAdmin, identifyUser, requireConsents, userController.GetRelated)
+	// quem está online agora só interessa a quem está logado
+	engine.GET("/users/online", identifyAdmin, identifyUser, middleware.RequireIdentified(), requireConsents,
+		presenceController.GetOnlineUsers)
+
+	// revela a existência de contas, por isso exige uma chave de admin ou de integração confiável
+	integrationKeys := append(append([]string{}, cfg.AdminAPIKeys...), cfg.TrustedAPIKeys...)
+	engine.POST("/users/check-duplicates", middleware.RequireAPIKey(integrationKeys), identifyAdmin,
+		bulkhead("duplicates", nil), userController.CheckDuplicates)
+	// o feed de alterações lista ids e a atividade de todos os usuários
+	engine.GET("/users/changes", middleware.RequireAPIKey(integrationKeys), userController.GetChanges)
+	engine.GET("/user/:id", identifyAdmin, identifyUser, requireConsents, userController.GetUser)
+	// POST /user é o cadastro antigo, mantido apenas por compatibilidade com /auth/register
+	engine.POST("/user", middleware.Deprecated(middleware.Deprecation{
+		Since: time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC),
+		Link:  "/auth/register",
+	}), requireCaptcha, userController.Register)
+	engine.POST("/auth/register", requireCaptcha, userController.Register)
+	engine.POST("/auth/login", authController.Login)
+	engine.POST("/auth/refresh", authController.Refresh)
+
+	// consentimentos e sessões continuam acessíveis mesmo com termos pendentes
+	self := engine.Group("/user/:id", middleware.Authenticate(keySet, revocations), middleware.RequireSelf("id"))
+	self.GET("/consents/pending", consentController.GetPendingConsents)
+	self.POST("/consents", consentController.AcceptConsent)
+	self.GET("/sessions", sessionController.GetSessions)
+	self.DELETE("/sessions", sessionController.RevokeSession)
+	self.DELETE("/sessions/:sid", sessionController.RevokeSession)
+
+	consented := self.Group("", requireConsents)
+	consented.GET("/preferences", preferencesController.GetPreferences)
+	consented.PUT("/preferences", preferencesController.UpdatePreferences)
+	consented.PUT("/avatar", bulkhead("avatars", nil), avatarController.UploadAvatar)
+	consented.POST("/password", passwordController.ChangePassword)
+
+	engine.GET("/usage", middleware.RequireAPIKey(cfg.TrustedAPIKeys), usageController.GetKeyUsage)
+	engine.POST("/presence/heartbeat", middleware.Authenticate(keySet, revocations), requireConsents,
+		presenceController.Heartbeat)
+
+	engine.GET("/.well-known/jwks.json", keyController.JWKS)
+	engine.POST("/webhooks/email/:provider", emailWebhookController.HandleEvents)
+
+	engine.GET("/tasks/:id", ipFilter.Group("admin"), middleware.RequireAPIKey(cfg.AdminAPIKeys), taskController.GetTask)
+
+	admin := engine.Group("/admin", ipFilter.Group("admin"), middleware.RequireAPIKey(cfg.AdminAPIKeys))
+	admin.POST("/keys/rotate", keyController.Rotate)
+	admin.POST("/users", userController.CreateUser)
+	admin.GET("/users/:id", moderationController.GetUser)
+	admin.DELETE("/users/:id", userController.DeleteUser)
+	admin.POST("/users/export", taskController.ExportUsers)
+	admin.POST("/users/import", taskController.ImportUsers)
+	admin.POST("/users/:id/anonymize", taskController.AnonymizeUser)
+	admin.Static("/exports", exportStore.Dir())
+	admin.POST("/invitations", invitationController.Create)
+	admin.GET("/invitations", invitationController.List)
+	admin.GET("/invitations/:id", invitationController.Get)
+	admin.DELETE("/invitations/:id", invitationController.Revoke)
+	admin.GET("/moderation/users", moderationController.GetFlagged)
+	admin.POST("/moderation/users/:id", moderationController.Review)
+	admin.POST("/reindex", maintenanceController.Reindex)
+	admin.GET("/maintenance", maintenanceController.GetMode)
+	admin.PUT("/maintenance", maintenanceController.SetMode)
+	admin.GET("/usage", usageController.GetReport)
+	admin.GET("/config/export", configController.Export)
+	admin.POST("/config/import", configController.Import)
+	admin.GET("/queues", jobController.GetQueues)
+	admin.GET("/jobs", jobController.GetJobs)
+	admin.GET("/jobs/:id", jobController.GetJob)
+	admin.POST("/jobs/:id/retry", jobController.RetryJob)
+
+	for _, register := range s.routes {
+		register(engine)
+	}
+
+	if injector != nil {
+		injector.Arm()
+	}
+
+	errs := make(chan error, 2)
+	servers := []*http.Server{}
+
+	if cfg.MTLSAddr != "" {
+		tlsConfig, err := auth.ClientTLSConfig(cfg.MTLSClientCAFile)
+		if err != nil {
+			return err
+		}
+
+		// listener interno: mesmas rotas, mas só aceita clientes com certificado válido
+		internalServer := &http.Server{
+			Addr:      cfg.MTLSAddr,
+			Handler:   engine,
+			TLSConfig: tlsConfig,
+		}
+		servers = append(servers, internalServer)
+		go func() {
+			errs <- internalServer.ListenAndServeTLS(cfg.MTLSCertFile, cfg.MTLSKeyFile)
+		}()
+	}
+
+	publicServer := &http.Server{Addr: ":8080", Handler: engine}
+	servers = append(servers, publicServer)
+	go func() {
+		errs <- publicServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+	}
+
+	// encerramento: para de aceitar conexões, conclui as requisições em andamento e
+	// espera o worker devolver o job interrompido à fila
+	log.Print("encerrando")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	for _, server := range servers {
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("encerrando %s: %v", server.Addr, err)
+		}
+	}
+	worker.Wait()
+
+	return nil
+}
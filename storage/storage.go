@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type BlobStore interface {
+	// Put grava o conteúdo e retorna a URL pública do objeto
+	Put(ctx context.Context, key string, content io.Reader) (string, error)
+	// DeletePrefix apaga todos os objetos cuja chave começa com prefix/ (ex.: os avatares de um usuário)
+	DeletePrefix(ctx context.Context, prefix string) error
+}
+
+// LocalStore grava os objetos em disco; baseURL é o prefixo pelo qual o diretório é servido
+type LocalStore struct {
+	dir     string
+	baseURL string
+}
+
+func NewLocalStore(dir, baseURL string) (LocalStore, error) {
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return LocalStore{}, err
+	}
+
+	return LocalStore{
+		dir:     dir,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+	}, nil
+}
+
+func (s LocalStore) Dir() string {
+	return s.dir
+}
+
+func (s LocalStore) Put(ctx context.Context, key string, content io.Reader) (string, error) {
+	path := filepath.Join(s.dir, filepath.Clean("/"+key))
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return "", err
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, content); err != nil {
+		return "", err
+	}
+
+	return s.baseURL + "/" + strings.TrimPrefix(filepath.ToSlash(filepath.Clean("/"+key)), "/"), nil
+}
+
+func (s LocalStore) DeletePrefix(ctx context.Context, prefix string) error {
+	path := filepath.Join(s.dir, filepath.Clean("/"+prefix))
+	if path == filepath.Clean(s.dir) {
+		return nil
+	}
+	return os.RemoveAll(path)
+}
+
+// Check confirma que o diretório existe e aceita escrita
+func (s LocalStore) Check(ctx context.Context) error {
+	probe, err := os.CreateTemp(s.dir, ".probe-*")
+	if err != nil {
+		return err
+	}
+	probe.Close()
+
+	return os.Remove(probe.Name())
+}
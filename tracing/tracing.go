@@ -0,0 +1,77 @@
+// Package tracing propaga o W3C Trace Context (header traceparent) sem depender de um
+// SDK de tracing: o trace id recebido segue nas chamadas de saída e nos exemplares
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"strings"
+)
+
+// Parent é o traceparent recebido ("00-<trace id>-<span id>-<flags>")
+type Parent struct {
+	TraceID string
+	SpanID  string
+	Sampled bool
+}
+
+// Parse valida o header; ok é false quando ausente ou malformado
+func Parse(traceparent string) (parent Parent, ok bool) {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return Parent{}, false
+	}
+	if !isLowerHex(parts[1]) || !isLowerHex(parts[2]) || strings.Trim(parts[1], "0") == "" {
+		return Parent{}, false
+	}
+
+	flags, err := strconv.ParseUint(parts[3], 16, 8)
+	if err != nil {
+		return Parent{}, false
+	}
+	return Parent{TraceID: parts[1], SpanID: parts[2], Sampled: flags&0x01 != 0}, true
+}
+
+// SampledTraceID devolve o trace id apenas quando o trace foi amostrado: os demais não
+// existem no backend de tracing
+func SampledTraceID(traceparent string) string {
+	parent, ok := Parse(traceparent)
+	if !ok || !parent.Sampled {
+		return ""
+	}
+	return parent.TraceID
+}
+
+// Child monta o traceparent de uma chamada de saída: mesmo trace, novo span id
+func (p Parent) Child() string {
+	span := make([]byte, 8)
+	rand.Read(span)
+
+	flags := "00"
+	if p.Sampled {
+		flags = "01"
+	}
+	return "00-" + p.TraceID + "-" + hex.EncodeToString(span) + "-" + flags
+}
+
+type contextKey struct{}
+
+func NewContext(ctx context.Context, parent Parent) context.Context {
+	return context.WithValue(ctx, contextKey{}, parent)
+}
+
+func FromContext(ctx context.Context) (Parent, bool) {
+	parent, ok := ctx.Value(contextKey{}).(Parent)
+	return parent, ok
+}
+
+func isLowerHex(s string) bool {
+	for _, c := range s {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
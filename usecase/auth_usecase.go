@@ -0,0 +1,199 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pytsx/goapi/auth"
+	"github.com/pytsx/goapi/events"
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrInvalidCredentials  = errors.New("credenciais inválidas")
+	ErrInvalidRefreshToken = errors.New("refresh token inválido")
+)
+
+// hash de uma senha que ninguém tem, comparado quando o email não existe
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("senha-de-comparacao"), bcrypt.DefaultCost)
+
+type AuthUsecase struct {
+	userRepository    repository.UserRepository
+	loginRepository   repository.LoginRepository
+	sessionRepository repository.SessionRepository
+	keySet            *auth.KeySet
+	revocations       auth.RevocationStore
+	tokenTTL          time.Duration
+	refreshTTL        time.Duration
+	dispatcher        *events.Dispatcher
+}
+
+func NewAuthUsecase(userRepo repository.UserRepository, loginRepo repository.LoginRepository,
+	sessionRepo repository.SessionRepository, keySet *auth.KeySet, revocations auth.RevocationStore,
+	tokenTTL, refreshTTL time.Duration, dispatcher *events.Dispatcher) AuthUsecase {
+	return AuthUsecase{
+		userRepository:    userRepo,
+		loginRepository:   loginRepo,
+		sessionRepository: sessionRepo,
+		keySet:            keySet,
+		revocations:       revocations,
+		tokenTTL:          tokenTTL,
+		refreshTTL:        refreshTTL,
+		dispatcher:        dispatcher,
+	}
+}
+
+// Login valida as credenciais, abre uma sessão e registra o acesso no histórico.
+// `login` traz o dispositivo e a origem da requisição.
+func (au *AuthUsecase) Login(credentials model.Credentials, login model.Login) (model.Token, error) {
+	// o cadastro grava o email normalizado (trim, minúsculas); a busca ignora maiúsculas
+	user, err := au.userRepository.GetUserByEmail(strings.TrimSpace(credentials.Email))
+	if err != nil {
+		return model.Token{}, err
+	}
+
+	if user == nil || user.PasswordHash == "" {
+		// compara mesmo assim, para o tempo de resposta não revelar quais contas existem
+		bcrypt.CompareHashAndPassword(dummyPasswordHash, []byte(credentials.Password))
+		return model.Token{}, ErrInvalidCredentials
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(credentials.Password))
+	if err != nil {
+		return model.Token{}, ErrInvalidCredentials
+	}
+
+	login.UserID = user.ID
+	if err := au.checkLogin(login); err != nil {
+		return model.Token{}, err
+	}
+
+	sessionID, err := newSessionID()
+	if err != nil {
+		return model.Token{}, err
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return model.Token{}, err
+	}
+
+	session := model.Session{
+		ID:               sessionID,
+		UserID:           user.ID,
+		Device:           login.Device,
+		IP:               login.IP,
+		ExpiresAt:        time.Now().Add(au.refreshTTL),
+		RefreshTokenHash: hashSecret(secret),
+	}
+	if err := au.sessionRepository.CreateSession(session); err != nil {
+		return model.Token{}, err
+	}
+
+	return au.issueToken(session, secret)
+}
+
+// Refresh troca um refresh token válido por um novo par de tokens, rotacionando o segredo
+func (au *AuthUsecase) Refresh(refreshToken, ip string) (model.Token, error) {
+	sessionID, secret, ok := strings.Cut(refreshToken, ".")
+	if !ok {
+		return model.Token{}, ErrInvalidRefreshToken
+	}
+
+	newSecret, err := newSecret()
+	if err != nil {
+		return model.Token{}, err
+	}
+
+	session, err := au.sessionRepository.RotateRefreshToken(sessionID, hashSecret(secret), hashSecret(newSecret), ip)
+	if err != nil {
+		return model.Token{}, err
+	}
+	if session != nil {
+		return au.issueToken(*session, newSecret)
+	}
+
+	// um token já rotacionado foi apresentado de novo: ele vazou ou houve corrida entre dois
+	// clientes com a mesma sessão. Revoga a sessão, invalidando também o token mais recente
+	userID, err := au.sessionRepository.RevokeReusedToken(sessionID, hashSecret(secret))
+	if err != nil {
+		return model.Token{}, err
+	}
+	if userID != 0 {
+		au.revocations.Revoke(sessionID, time.Now().Add(au.tokenTTL))
+		log.Printf("refresh token reutilizado: sessão %s do usuário %d revogada", sessionID, userID)
+	}
+	return model.Token{}, ErrInvalidRefreshToken
+}
+
+func (au *AuthUsecase) issueToken(session model.Session, secret string) (model.Token, error) {
+	token, err := au.keySet.Sign(auth.Claims{
+		Subject:   strconv.Itoa(session.UserID),
+		SessionID: session.ID,
+	})
+	if err != nil {
+		return model.Token{}, err
+	}
+
+	return model.Token{
+		AccessToken:  token,
+		TokenType:    "Bearer",
+		ExpiresIn:    int(au.tokenTTL.Seconds()),
+		RefreshToken: session.ID + "." + secret,
+	}, nil
+}
+
+// compara o acesso com o histórico antes de registrá-lo; o primeiro login nunca é suspeito
+func (au *AuthUsecase) checkLogin(login model.Login) error {
+	hasHistory, knownDevice, knownLocation, err := au.loginRepository.KnownLogin(login)
+	if err != nil {
+		return err
+	}
+
+	id, err := au.loginRepository.RecordLogin(login)
+	if err != nil {
+		return err
+	}
+	login.ID = id
+	login.CreatedAt = time.Now()
+
+	if hasHistory && (!knownDevice || !knownLocation) {
+		au.dispatcher.Publish(events.SuspiciousLogin, model.SuspiciousLogin{
+			Login:       login,
+			NewDevice:   !knownDevice,
+			NewLocation: !knownLocation,
+		})
+	}
+
+	return nil
+}
+
+func newSessionID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+func newSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
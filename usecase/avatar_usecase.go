@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/pytsx/goapi/events"
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/repository"
+	"github.com/pytsx/goapi/scanner"
+	"github.com/pytsx/goapi/storage"
+)
+
+var (
+	ErrUnsupportedImage = errors.New("formato de imagem não suportado")
+	ErrAvatarRejected   = errors.New("avatar reprovado na verificação de conteúdo")
+)
+
+var avatarExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+type AvatarUsecase struct {
+	userRepository       repository.UserRepository
+	moderationRepository repository.ModerationRepository
+	store                storage.BlobStore
+	quarantine           storage.BlobStore
+	scanner              scanner.Scanner
+	dispatcher           *events.Dispatcher
+}
+
+func NewAvatarUsecase(userRepo repository.UserRepository, moderationRepo repository.ModerationRepository,
+	store, quarantine storage.BlobStore, s scanner.Scanner, dispatcher *events.Dispatcher) AvatarUsecase {
+	return AvatarUsecase{
+		userRepository:       userRepo,
+		moderationRepository: moderationRepo,
+		store:                store,
+		quarantine:           quarantine,
+		scanner:              s,
+		dispatcher:           dispatcher,
+	}
+}
+
+// UploadAvatar só publica a imagem depois de aprovada pelos scanners;
+// imagens reprovadas vão para a quarentena para análise de um admin
+func (au *AvatarUsecase) UploadAvatar(ctx context.Context, userID int, content []byte) (*model.User, error) {
+	extension, ok := avatarExtensions[http.DetectContentType(content)]
+	if !ok {
+		return nil, ErrUnsupportedImage
+	}
+
+	user, err := au.userRepository.GetUser(userID)
+	if err != nil || user == nil {
+		return nil, err
+	}
+
+	key := fmt.Sprintf("%d/%d%s", userID, time.Now().UnixNano(), extension)
+
+	result, err := au.scanner.Scan(ctx, content)
+	if err != nil {
+		return nil, err
+	}
+
+	if !result.Clean {
+		if _, err := au.quarantine.Put(ctx, key, bytes.NewReader(content)); err != nil {
+			return nil, err
+		}
+		if err := au.moderationRepository.QuarantineAvatar(userID, key, result.Reason); err != nil {
+			return nil, err
+		}
+		return nil, ErrAvatarRejected
+	}
+
+	imgURL, err := au.store.Put(ctx, key, bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := au.userRepository.UpdateAvatar(userID, imgURL); err != nil {
+		return nil, err
+	}
+
+	au.dispatcher.Publish(events.ProfileUpdated, model.ProfileUpdated{UserID: userID})
+
+	user.ImgURL = model.NullString(imgURL)
+	return user, nil
+}
@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/repository"
+)
+
+var ErrConfigConflict = errors.New("a configuração foi alterada desde a revisão informada")
+
+// RuntimeSettings lê e aplica os valores vigentes nos componentes em execução
+type RuntimeSettings interface {
+	Snapshot() model.RuntimeConfig
+	Apply(config model.RuntimeConfig)
+}
+
+type ConfigUsecase struct {
+	repository      repository.RuntimeConfigRepository
+	auditRepository repository.AuditRepository
+	transactor      repository.Transactor
+	settings        RuntimeSettings
+	// revisão aplicada nesta instância; Watch reaplica quando outra instância grava uma nova
+	applied *atomic.Int64
+}
+
+func NewConfigUsecase(repo repository.RuntimeConfigRepository, auditRepo repository.AuditRepository,
+	transactor repository.Transactor, settings RuntimeSettings) ConfigUsecase {
+	return ConfigUsecase{
+		repository:      repo,
+		auditRepository: auditRepo,
+		transactor:      transactor,
+		settings:        settings,
+		applied:         &atomic.Int64{},
+	}
+}
+
+// Restore reaplica a última revisão importada; chamado no boot, prevalece sobre as variáveis de ambiente
+func (cu *ConfigUsecase) Restore() (bool, error) {
+	latest, err := cu.repository.Latest()
+	if err != nil || latest == nil {
+		return false, err
+	}
+
+	cu.settings.Apply(*latest)
+	cu.applied.Store(latest.Revision)
+	return true, nil
+}
+
+// Watch consulta periodicamente a última revisão, para que importações e mudanças de
+// manutenção feitas em outra instância valham em todas
+func (cu *ConfigUsecase) Watch(interval time.Duration) {
+	go func() {
+		for range time.Tick(interval) {
+			latest, err := cu.repository.Latest()
+			if err != nil {
+				log.Printf("config: mantendo a revisão %d, falha ao consultar a última: %v", cu.applied.Load(), err)
+				continue
+			}
+			if latest == nil || latest.Revision == cu.applied.Load() {
+				continue
+			}
+
+			cu.settings.Apply(*latest)
+			cu.applied.Store(latest.Revision)
+			log.Printf("config: revisão %d aplicada", latest.Revision)
+		}
+	}()
+}
+
+// Export devolve os valores vigentes, incluindo alterações feitas por outras rotas (ex.: manutenção)
+func (cu *ConfigUsecase) Export() (model.RuntimeConfig, error) {
+	latest, err := cu.repository.Latest()
+	if err != nil {
+		return model.RuntimeConfig{}, err
+	}
+
+	config := cu.settings.Snapshot()
+	config.SchemaVersion = model.RuntimeConfigSchema
+	if latest != nil {
+		config.Revision = latest.Revision
+	}
+	now := time.Now().UTC()
+	config.ExportedAt = &now
+
+	return config, nil
+}
+
+// Import valida o documento, grava uma nova revisão e a aplica imediatamente. expectedRevision
+// é a revisão vigente que o chamador viu (nil não confere); o revision do documento só
+// identifica a origem, que pode ser outro ambiente
+func (cu *ConfigUsecase) Import(config model.RuntimeConfig, expectedRevision *int64, actor model.Actor) (model.RuntimeConfig, error) {
+	if err := validateRuntimeConfig(config); err != nil {
+		return model.RuntimeConfig{}, err
+	}
+
+	err := cu.transactor.WithinTx(func(tx *sql.Tx) error {
+		configs := cu.repository.WithTx(tx)
+		if err := configs.Lock(); err != nil {
+			return err
+		}
+
+		latest, err := configs.Latest()
+		if err != nil {
+			return err
+		}
+		current := int64(0)
+		if latest != nil {
+			current = latest.Revision
+		}
+		if expectedRevision != nil && *expectedRevision != current {
+			return ErrConfigConflict
+		}
+		source := config.Revision
+
+		revision, err := configs.Save(config, actor.ID)
+		if err != nil {
+			return err
+		}
+		config.Revision = revision
+
+		audit := cu.auditRepository.WithTx(tx)
+		return audit.Record(model.AuditEntry{
+			Actor:    actor,
+			Action:   "config.imported",
+			Entity:   "runtime_config",
+			EntityID: strconv.FormatInt(revision, 10),
+			Metadata: map[string]any{
+				"previous_revision": current,
+				"source_revision":   source,
+			},
+		})
+	})
+	if err != nil {
+		return model.RuntimeConfig{}, err
+	}
+
+	cu.settings.Apply(config)
+	cu.applied.Store(config.Revision)
+	config.ExportedAt = nil
+	return config, nil
+}
+
+// SetMaintenance grava uma nova revisão igual à vigente, mudando apenas o modo de
+// manutenção, para que as demais instâncias o recebam pelo Watch
+func (cu *ConfigUsecase) SetMaintenance(enabled bool, actor model.Actor) error {
+	var config model.RuntimeConfig
+	err := cu.transactor.WithinTx(func(tx *sql.Tx) error {
+		configs := cu.repository.WithTx(tx)
+		if err := configs.Lock(); err != nil {
+			return err
+		}
+
+		latest, err := configs.Latest()
+		if err != nil {
+			return err
+		}
+		if latest != nil {
+			config = *latest
+		} else {
+			config = cu.settings.Snapshot()
+			config.SchemaVersion = model.RuntimeConfigSchema
+		}
+		previous := config.Revision
+		config.Flags.Maintenance = enabled
+
+		revision, err := configs.Save(config, actor.ID)
+		if err != nil {
+			return err
+		}
+		config.Revision = revision
+
+		audit := cu.auditRepository.WithTx(tx)
+		return audit.Record(model.AuditEntry{
+			Actor:    actor,
+			Action:   "config.maintenance_changed",
+			Entity:   "runtime_config",
+			EntityID: strconv.FormatInt(revision, 10),
+			Metadata: map[string]any{
+				"previous_revision": previous,
+				"enabled":           enabled,
+			},
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	cu.settings.Apply(config)
+	cu.applied.Store(config.Revision)
+	return nil
+}
+
+func validateRuntimeConfig(config model.RuntimeConfig) error {
+	fields := map[string]string{}
+
+	if config.SchemaVersion != model.RuntimeConfigSchema {
+		fields["schema_version"] = "versão de esquema não suportada, use " + strconv.Itoa(model.RuntimeConfigSchema)
+	}
+	if config.Revision < 0 {
+		fields["revision"] = "não pode ser negativa"
+	}
+	if config.Quotas.MaxUsers < 0 {
+		fields["quotas.max_users"] = "não pode ser negativo (zero desativa)"
+	}
+	if config.Quotas.DailyRequests < 0 {
+		fields["quotas.daily_requests"] = "não pode ser negativo (zero desativa)"
+	}
+
+	if len(fields) > 0 {
+		return ValidationError{Fields: fields}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/repository"
+)
+
+var ErrOutdatedPolicyVersion = errors.New("versão de política desconhecida ou desatualizada")
+
+type ConsentUsecase struct {
+	repository repository.ConsentRepository
+	// política => versão vigente
+	policies map[string]string
+}
+
+func NewConsentUsecase(repo repository.ConsentRepository, policies map[string]string) ConsentUsecase {
+	return ConsentUsecase{
+		repository: repo,
+		policies:   policies,
+	}
+}
+
+// PendingConsents lista as políticas cuja versão vigente o usuário ainda não aceitou
+func (cu *ConsentUsecase) PendingConsents(userID int) ([]model.Consent, error) {
+	consents, err := cu.repository.GetConsents(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	accepted := map[string]bool{}
+	for _, consent := range consents {
+		accepted[consent.Policy+"@"+consent.Version] = true
+	}
+
+	pending := []model.Consent{}
+	for policy, version := range cu.policies {
+		if !accepted[policy+"@"+version] {
+			pending = append(pending, model.Consent{Policy: policy, Version: version})
+		}
+	}
+
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].Policy < pending[j].Policy
+	})
+
+	return pending, nil
+}
+
+// AcceptConsent só aceita a versão vigente da política
+func (cu *ConsentUsecase) AcceptConsent(userID int, consent model.Consent, ip string) error {
+	if current, ok := cu.policies[consent.Policy]; !ok || current != consent.Version {
+		return ErrOutdatedPolicyVersion
+	}
+
+	return cu.repository.RecordConsent(userID, consent, ip)
+}
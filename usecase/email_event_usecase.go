@@ -0,0 +1,32 @@
+package usecase
+
+import (
+	"github.com/pytsx/goapi/events"
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/repository"
+)
+
+type EmailEventUsecase struct {
+	repository repository.SuppressionRepository
+	dispatcher *events.Dispatcher
+}
+
+func NewEmailEventUsecase(repo repository.SuppressionRepository, dispatcher *events.Dispatcher) EmailEventUsecase {
+	return EmailEventUsecase{
+		repository: repo,
+		dispatcher: dispatcher,
+	}
+}
+
+func (eu *EmailEventUsecase) RecordEvents(emailEvents []model.EmailEvent) error {
+	for _, event := range emailEvents {
+		userIDs, err := eu.repository.Suppress(event)
+		if err != nil {
+			return err
+		}
+		for _, userID := range userIDs {
+			eu.dispatcher.Publish(events.ProfileUpdated, model.ProfileUpdated{UserID: userID})
+		}
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/repository"
+)
+
+const (
+	maxInvitationUses = 10000
+	// caracteres do código mostrados na listagem
+	invitationHintLength = 4
+)
+
+// o cadastro público está restrito a convidados e o código informado não é válido
+var ErrInvitationRequired = errors.New("o cadastro exige um convite válido")
+
+type InvitationUsecase struct {
+	repository      repository.InvitationRepository
+	auditRepository repository.AuditRepository
+	transactor      repository.Transactor
+	// página de cadastro que recebe o código em ?invite=; vazio não gera links
+	linkBase string
+	// compartilhado entre as cópias, alternado pela importação de configuração
+	required *atomic.Bool
+}
+
+func NewInvitationUsecase(repo repository.InvitationRepository, auditRepo repository.AuditRepository,
+	transactor repository.Transactor, linkBase string, required bool) InvitationUsecase {
+	iu := InvitationUsecase{
+		repository:      repo,
+		auditRepository: auditRepo,
+		transactor:      transactor,
+		linkBase:        linkBase,
+		required:        &atomic.Bool{},
+	}
+	iu.required.Store(required)
+	return iu
+}
+
+// WithContext devolve uma cópia cujas consultas respeitam o deadline de ctx
+func (iu InvitationUsecase) WithContext(ctx context.Context) InvitationUsecase {
+	iu.repository = iu.repository.WithContext(ctx)
+	iu.auditRepository = iu.auditRepository.WithContext(ctx)
+	iu.transactor = iu.transactor.WithContext(ctx)
+	return iu
+}
+
+// Required indica se o cadastro público exige convite
+func (iu *InvitationUsecase) Required() bool {
+	return iu.required.Load()
+}
+
+func (iu *InvitationUsecase) SetRequired(required bool) {
+	iu.required.Store(required)
+}
+
+// Create gera o código e grava o convite. O código só é devolvido aqui: o banco guarda o hash
+func (iu *InvitationUsecase) Create(request model.InvitationRequest, actor model.Actor) (model.Invitation, error) {
+	if err := validateInvitation(request); err != nil {
+		return model.Invitation{}, err
+	}
+
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return model.Invitation{}, err
+	}
+	code := base64.RawURLEncoding.EncodeToString(buf)
+
+	var invitation model.Invitation
+	err := iu.transactor.WithinTx(func(tx *sql.Tx) error {
+		invitations := iu.repository.WithTx(tx)
+		var err error
+		invitation, err = invitations.Create(hashSecret(code), code[:invitationHintLength], request, actor.ID)
+		if err != nil {
+			return err
+		}
+
+		audit := iu.auditRepository.WithTx(tx)
+		return audit.Record(model.AuditEntry{
+			Actor:    actor,
+			Action:   "invitation.created",
+			Entity:   "invitation",
+			EntityID: strconv.FormatInt(invitation.ID, 10),
+			Metadata: map[string]any{
+				"max_uses":   request.MaxUses,
+				"expires_at": request.ExpiresAt,
+			},
+		})
+	})
+	if err != nil {
+		return model.Invitation{}, err
+	}
+
+	invitation.Code = code
+	invitation.Link = iu.link(code)
+	invitation.Status = invitationStatus(invitation, time.Now())
+	return invitation, nil
+}
+
+func (iu *InvitationUsecase) List(limit, offset int) ([]model.Invitation, error) {
+	invitations, err := iu.repository.List(limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range invitations {
+		invitations[i].Status = invitationStatus(invitations[i], now)
+	}
+	return invitations, nil
+}
+
+// GetInvitation devolve o convite e os cadastros feitos com ele; nil quando não existe
+func (iu *InvitationUsecase) GetInvitation(id int64) (*model.Invitation, []model.InvitationUse, error) {
+	invitation, err := iu.repository.GetInvitation(id)
+	if err != nil || invitation == nil {
+		return nil, nil, err
+	}
+	invitation.Status = invitationStatus(*invitation, time.Now())
+
+	uses, err := iu.repository.GetUses(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return invitation, uses, nil
+}
+
+// Revoke invalida o convite para novos cadastros; retorna false se ele não existe
+func (iu *InvitationUsecase) Revoke(id int64, actor model.Actor) (bool, error) {
+	var found bool
+	err := iu.transactor.WithinTx(func(tx *sql.Tx) error {
+		invitations := iu.repository.WithTx(tx)
+		var err error
+		found, err = invitations.Revoke(id)
+		if err != nil || !found {
+			return err
+		}
+
+		audit := iu.auditRepository.WithTx(tx)
+		return audit.Record(model.AuditEntry{
+			Actor:    actor,
+			Action:   "invitation.revoked",
+			Entity:   "invitation",
+			EntityID: strconv.FormatInt(id, 10),
+		})
+	})
+	return found, err
+}
+
+// redeem consome um uso do convite dentro da transação do cadastro; 0 quando o código não vale
+func (iu *InvitationUsecase) redeem(tx *sql.Tx, code string) (int64, error) {
+	invitations := iu.repository.WithTx(tx)
+	return invitations.Redeem(hashSecret(code))
+}
+
+func (iu *InvitationUsecase) recordUse(tx *sql.Tx, invitationID int64, userID int) error {
+	invitations := iu.repository.WithTx(tx)
+	return invitations.RecordUse(invitationID, userID)
+}
+
+func (iu *InvitationUsecase) link(code string) model.NullString {
+	if iu.linkBase == "" {
+		return ""
+	}
+
+	link, err := url.Parse(iu.linkBase)
+	if err != nil {
+		return ""
+	}
+	query := link.Query()
+	query.Set("invite", code)
+	link.RawQuery = query.Encode()
+	return model.NullString(link.String())
+}
+
+func invitationStatus(invitation model.Invitation, now time.Time) string {
+	switch {
+	case !invitation.RevokedAt.IsZero():
+		return model.InvitationRevoked
+	case invitation.Uses >= invitation.MaxUses:
+		return model.InvitationExhausted
+	case !invitation.ExpiresAt.IsZero() && !invitation.ExpiresAt.After(now):
+		return model.InvitationExpired
+	default:
+		return model.InvitationActive
+	}
+}
@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/repository"
+)
+
+var ErrJobNotDead = errors.New("o job não existe ou não está na fila morta")
+
+type JobUsecase struct {
+	jobRepository   repository.JobRepository
+	auditRepository repository.AuditRepository
+}
+
+func NewJobUsecase(jobRepo repository.JobRepository, auditRepo repository.AuditRepository) JobUsecase {
+	return JobUsecase{
+		jobRepository:   jobRepo,
+		auditRepository: auditRepo,
+	}
+}
+
+func (ju *JobUsecase) GetQueues() ([]model.JobQueueStats, error) {
+	return ju.jobRepository.Stats()
+}
+
+func (ju *JobUsecase) GetJobs(filter model.JobFilter, limit, offset int) ([]model.Job, error) {
+	return ju.jobRepository.List(filter, limit, offset)
+}
+
+func (ju *JobUsecase) GetJob(id int64) (*model.Job, error) {
+	return ju.jobRepository.GetJob(id)
+}
+
+// RetryDead reenfileira um job da fila morta
+func (ju *JobUsecase) RetryDead(id int64, actor model.Actor) error {
+	retried, err := ju.jobRepository.Retry(id)
+	if err != nil {
+		return err
+	}
+	if !retried {
+		return ErrJobNotDead
+	}
+
+	return ju.auditRepository.Record(model.AuditEntry{
+		Actor:    actor,
+		Action:   "job.retried",
+		Entity:   "job",
+		EntityID: strconv.FormatInt(id, 10),
+	})
+}
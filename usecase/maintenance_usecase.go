@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/repository"
+)
+
+type MaintenanceUsecase struct {
+	taskUsecase           TaskUsecase
+	maintenanceRepository repository.MaintenanceRepository
+}
+
+func NewMaintenanceUsecase(taskUsecase TaskUsecase, maintenanceRepo repository.MaintenanceRepository) MaintenanceUsecase {
+	return MaintenanceUsecase{
+		taskUsecase:           taskUsecase,
+		maintenanceRepository: maintenanceRepo,
+	}
+}
+
+// RequestReindex cria a task de reindexação, executada pelo worker
+func (mu *MaintenanceUsecase) RequestReindex(actor model.Actor) (model.Task, error) {
+	return mu.taskUsecase.Submit(model.TaskReindex, nil, actor)
+}
+
+// Reindex é a task de reindexação; o REINDEX não informa progresso intermediário
+func (mu *MaintenanceUsecase) Reindex(ctx context.Context, _ model.TaskPayload, _ func(int)) (TaskResult, error) {
+	return TaskResult{}, mu.maintenanceRepository.Reindex(ctx)
+}
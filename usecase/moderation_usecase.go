@@ -0,0 +1,50 @@
+package usecase
+
+import (
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/repository"
+)
+
+type ModerationUsecase struct {
+	repository     repository.ModerationRepository
+	userRepository repository.UserRepository
+}
+
+func NewModerationUsecase(repo repository.ModerationRepository, userRepo repository.UserRepository) ModerationUsecase {
+	return ModerationUsecase{
+		repository:     repo,
+		userRepository: userRepo,
+	}
+}
+
+func (mu *ModerationUsecase) GetFlagged() ([]model.ModerationCase, error) {
+	return mu.repository.GetCases(model.ModerationFlagged)
+}
+
+// Review aplica a decisão do admin; retorna false se o usuário não existe
+func (mu *ModerationUsecase) Review(userID int, review model.ModerationReview) (bool, error) {
+	status := model.ModerationApproved
+	if review.Action == model.ModerationReject {
+		status = model.ModerationRejected
+	}
+
+	return mu.repository.UpdateStatus(userID, status)
+}
+
+// GetAdminUser retorna o usuário com a situação de moderação do nome e do avatar
+func (mu *ModerationUsecase) GetAdminUser(userID int) (*model.AdminUser, error) {
+	user, err := mu.userRepository.GetUser(userID)
+	if err != nil || user == nil {
+		return nil, err
+	}
+
+	moderation, err := mu.repository.GetUserModeration(userID)
+	if err != nil || moderation == nil {
+		return nil, err
+	}
+
+	return &model.AdminUser{
+		User:       *user,
+		Moderation: *moderation,
+	}, nil
+}
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/pytsx/goapi/events"
+	"github.com/pytsx/goapi/i18n"
+	"github.com/pytsx/goapi/mailer"
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/repository"
+)
+
+type NotificationUsecase struct {
+	userRepository        repository.UserRepository
+	preferencesRepository repository.PreferencesRepository
+	mailer                mailer.Mailer
+}
+
+func NewNotificationUsecase(userRepo repository.UserRepository, prefsRepo repository.PreferencesRepository,
+	m mailer.Mailer) NotificationUsecase {
+	return NotificationUsecase{
+		userRepository:        userRepo,
+		preferencesRepository: prefsRepo,
+		mailer:                m,
+	}
+}
+
+// NotifySuspiciousLogin envia o email "foi você?", respeitando a preferência do usuário
+func (nu *NotificationUsecase) NotifySuspiciousLogin(event events.Event) {
+	suspicious := event.Payload.(model.SuspiciousLogin)
+	login := suspicious.Login
+
+	prefs, err := nu.preferencesRepository.GetPreferences(login.UserID)
+	if err != nil {
+		log.Printf("notificação de login: %v", err)
+		return
+	}
+	if !prefs.LoginAlerts {
+		return
+	}
+
+	user, err := nu.userRepository.GetUser(login.UserID)
+	if err != nil || user == nil {
+		log.Printf("notificação de login: usuário %d não encontrado: %v", login.UserID, err)
+		return
+	}
+
+	locale := i18n.Match(prefs.Locale)
+	body := i18n.T(locale, "email.login_alert.body", user.Name, login.Device, login.Location, login.IP,
+		login.CreatedAt.Format(i18n.T(locale, "format.datetime")))
+
+	err = nu.mailer.Send(context.Background(), mailer.Message{
+		To:      user.Email,
+		Subject: i18n.T(locale, "email.login_alert.subject"),
+		Body:    body,
+	})
+	if err != nil {
+		log.Printf("notificação de login: %v", err)
+	}
+}
+
+// NotifyPasswordChanged avisa o dono da conta, para que perceba uma troca que não fez
+func (nu *NotificationUsecase) NotifyPasswordChanged(event events.Event) {
+	changed := event.Payload.(model.PasswordChanged)
+
+	user, err := nu.userRepository.GetUser(changed.UserID)
+	if err != nil || user == nil {
+		log.Printf("notificação de troca de senha: usuário %d não encontrado: %v", changed.UserID, err)
+		return
+	}
+
+	prefs, err := nu.preferencesRepository.GetPreferences(user.ID)
+	if err != nil {
+		log.Printf("notificação de troca de senha: %v", err)
+		return
+	}
+	locale := i18n.Match(prefs.Locale)
+
+	err = nu.mailer.Send(context.Background(), mailer.Message{
+		To:      user.Email,
+		Subject: i18n.T(locale, "email.password_changed.subject"),
+		Body: i18n.T(locale, "email.password_changed.body", user.Name,
+			event.OccurredAt.Format(i18n.T(locale, "format.datetime")), changed.Actor.IP),
+	})
+	if err != nil {
+		log.Printf("notificação de troca de senha: %v", err)
+	}
+}
+
+// SendWelcomeEmail é o handler do job de boas-vindas; um erro faz o job ser repetido
+func (nu *NotificationUsecase) SendWelcomeEmail(ctx context.Context, payload json.RawMessage) error {
+	var welcome model.WelcomeEmailPayload
+	if err := json.Unmarshal(payload, &welcome); err != nil {
+		return err
+	}
+
+	user, err := nu.userRepository.GetUser(welcome.UserID)
+	if err != nil {
+		return err
+	}
+	if user == nil {
+		// o usuário foi removido antes do envio; não há o que repetir
+		return nil
+	}
+
+	// o idioma salvo pelo usuário prevalece sobre o detectado no cadastro
+	prefs, err := nu.preferencesRepository.GetPreferences(user.ID)
+	if err != nil {
+		return err
+	}
+	locale := i18n.Match(prefs.Locale, welcome.Locale)
+
+	return nu.mailer.Send(ctx, mailer.Message{
+		To:      user.Email,
+		Subject: i18n.T(locale, "email.welcome.subject"),
+		Body:    i18n.T(locale, "email.welcome.body", user.Name),
+	})
+}
@@ -0,0 +1,112 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/pytsx/goapi/events"
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/repository"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// sem a senha atual, só uma sessão aberta há pouco (login recente) pode trocar a senha
+var ErrReauthRequired = errors.New("confirme a senha atual ou faça login novamente")
+
+type PasswordUsecase struct {
+	userRepository    repository.UserRepository
+	auditRepository   repository.AuditRepository
+	sessionRepository repository.SessionRepository
+	transactor        repository.Transactor
+	sessions          SessionUsecase
+	dispatcher        *events.Dispatcher
+	reauthWindow      time.Duration
+}
+
+func NewPasswordUsecase(userRepo repository.UserRepository, auditRepo repository.AuditRepository,
+	sessionRepo repository.SessionRepository, transactor repository.Transactor, sessions SessionUsecase,
+	dispatcher *events.Dispatcher, reauthWindow time.Duration) PasswordUsecase {
+	return PasswordUsecase{
+		userRepository:    userRepo,
+		auditRepository:   auditRepo,
+		sessionRepository: sessionRepo,
+		transactor:        transactor,
+		sessions:          sessions,
+		dispatcher:        dispatcher,
+		reauthWindow:      reauthWindow,
+	}
+}
+
+// ChangePassword confere a senha atual (ou a reautenticação recente da sessão `sessionID`),
+// aplica a política de senhas, grava o novo hash com auditoria e revoga as demais sessões
+func (pu *PasswordUsecase) ChangePassword(userID int, sessionID string, change model.PasswordChange,
+	actor model.Actor) error {
+	currentHash, err := pu.userRepository.GetPasswordHash(userID)
+	if err != nil {
+		return err
+	}
+
+	if change.CurrentPassword != "" {
+		if currentHash == "" || bcrypt.CompareHashAndPassword([]byte(currentHash), []byte(change.CurrentPassword)) != nil {
+			return ErrInvalidCredentials
+		}
+	} else if !pu.recentlyAuthenticated(sessionID) {
+		return ErrReauthRequired
+	}
+
+	if reason := checkPasswordPolicy(change.NewPassword); reason != "" {
+		return ValidationError{Fields: map[string]string{"new_password": reason}}
+	}
+	if currentHash != "" && bcrypt.CompareHashAndPassword([]byte(currentHash), []byte(change.NewPassword)) == nil {
+		return ValidationError{Fields: map[string]string{"new_password": "deve ser diferente da senha atual"}}
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(change.NewPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	err = pu.transactor.WithinTx(func(tx *sql.Tx) error {
+		users := pu.userRepository.WithTx(tx)
+		if err := users.UpdatePassword(userID, string(hash)); err != nil {
+			return err
+		}
+
+		audit := pu.auditRepository.WithTx(tx)
+		return audit.Record(model.AuditEntry{
+			Actor:    actor,
+			Action:   "user.password_changed",
+			Entity:   "user",
+			EntityID: strconv.Itoa(userID),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	revoked, err := pu.sessions.RevokeOtherSessions(userID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	pu.dispatcher.Publish(events.PasswordChanged, model.PasswordChanged{
+		UserID:          userID,
+		Actor:           actor,
+		RevokedSessions: len(revoked),
+	})
+	return nil
+}
+
+func (pu *PasswordUsecase) recentlyAuthenticated(sessionID string) bool {
+	if sessionID == "" {
+		return false
+	}
+
+	session, err := pu.sessionRepository.GetActiveSession(sessionID)
+	if err != nil || session == nil {
+		return false
+	}
+	return time.Since(session.CreatedAt) <= pu.reauthWindow
+}
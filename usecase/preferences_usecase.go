@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"github.com/pytsx/goapi/events"
+	"github.com/pytsx/goapi/i18n"
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/repository"
+)
+
+type PreferencesUsecase struct {
+	repository repository.PreferencesRepository
+	dispatcher *events.Dispatcher
+}
+
+func NewPreferencesUsecase(repo repository.PreferencesRepository, dispatcher *events.Dispatcher) PreferencesUsecase {
+	return PreferencesUsecase{
+		repository: repo,
+		dispatcher: dispatcher,
+	}
+}
+
+func (pu *PreferencesUsecase) GetPreferences(userID int) (model.Preferences, error) {
+	return pu.repository.GetPreferences(userID)
+}
+
+// UpdatePreferences aplica sobre as preferências atuais apenas os campos informados.
+// O locale é normalizado para um dos idiomas suportados; vazio volta a usar o da requisição.
+func (pu *PreferencesUsecase) UpdatePreferences(userID int, update model.PreferencesUpdate) (model.Preferences, error) {
+	prefs, err := pu.repository.GetPreferences(userID)
+	if err != nil {
+		return model.Preferences{}, err
+	}
+
+	if update.LoginAlerts != nil {
+		prefs.LoginAlerts = *update.LoginAlerts
+	}
+	if update.Locale != nil {
+		prefs.Locale = ""
+		if *update.Locale != "" {
+			locale, ok := i18n.Normalize(*update.Locale)
+			if !ok {
+				return model.Preferences{}, ValidationError{Fields: map[string]string{
+					"locale": "idioma não suportado, use pt-BR ou en",
+				}}
+			}
+			prefs.Locale = locale
+		}
+	}
+
+	if err := pu.repository.UpdatePreferences(userID, prefs); err != nil {
+		return model.Preferences{}, err
+	}
+
+	pu.dispatcher.Publish(events.ProfileUpdated, model.ProfileUpdated{UserID: userID})
+	return prefs, nil
+}
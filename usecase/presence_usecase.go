@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/presence"
+	"github.com/pytsx/goapi/repository"
+)
+
+type PresenceUsecase struct {
+	tracker        presence.Tracker
+	userRepository repository.UserRepository
+}
+
+func NewPresenceUsecase(tracker presence.Tracker, userRepo repository.UserRepository) PresenceUsecase {
+	return PresenceUsecase{
+		tracker:        tracker,
+		userRepository: userRepo,
+	}
+}
+
+func (pu *PresenceUsecase) Heartbeat(userID int) error {
+	return pu.tracker.Heartbeat(context.Background(), userID)
+}
+
+// OnlineUsers devolve os usuários com heartbeat dentro do TTL
+func (pu *PresenceUsecase) OnlineUsers() ([]model.User, error) {
+	ids, err := pu.tracker.List(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return []model.User{}, nil
+	}
+
+	users, err := pu.userRepository.GetUsersByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range users {
+		users[i].IsOnline = true
+	}
+	return users, nil
+}
@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"log"
+
+	"github.com/pytsx/goapi/events"
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/repository"
+)
+
+// critérios do profile_completeness; os pesos somam 100. A migração 0019 calcula o
+// valor inicial dos usuários existentes com os mesmos critérios
+var profileCriteria = []struct {
+	weight int
+	met    func(model.ProfileFacts) bool
+}{
+	{25, func(f model.ProfileFacts) bool { return f.HasAvatar }},
+	{25, func(f model.ProfileFacts) bool { return f.EmailDeliverable }},
+	{25, func(f model.ProfileFacts) bool { return f.HasPassword }},
+	{25, func(f model.ProfileFacts) bool { return f.HasLocale }},
+}
+
+func profileCompleteness(facts model.ProfileFacts) int {
+	completeness := 0
+	for _, criterion := range profileCriteria {
+		if criterion.met(facts) {
+			completeness += criterion.weight
+		}
+	}
+	return completeness
+}
+
+// ProfileUsecase mantém o profile_completeness atualizado a partir dos eventos de domínio
+type ProfileUsecase struct {
+	userRepository repository.UserRepository
+}
+
+func NewProfileUsecase(userRepo repository.UserRepository) ProfileUsecase {
+	return ProfileUsecase{
+		userRepository: userRepo,
+	}
+}
+
+// Recalculate lê os dados atuais do perfil e grava o novo percentual
+func (pu *ProfileUsecase) Recalculate(userID int) error {
+	facts, err := pu.userRepository.GetProfileFacts(userID)
+	if err != nil || facts == nil {
+		return err
+	}
+	return pu.userRepository.SetProfileCompleteness(userID, profileCompleteness(*facts))
+}
+
+// HandleEvent assina UserCreated, PasswordChanged e ProfileUpdated
+func (pu *ProfileUsecase) HandleEvent(event events.Event) {
+	var userID int
+	switch payload := event.Payload.(type) {
+	case model.User:
+		userID = payload.ID
+	case model.PasswordChanged:
+		userID = payload.UserID
+	case model.ProfileUpdated:
+		userID = payload.UserID
+	default:
+		log.Printf("preenchimento do perfil: payload %T inesperado em %s", event.Payload, event.Name)
+		return
+	}
+
+	if err := pu.Recalculate(userID); err != nil {
+		log.Printf("preenchimento do perfil: usuário %d: %v", userID, err)
+	}
+}
@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"time"
+
+	"github.com/pytsx/goapi/auth"
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/repository"
+)
+
+type SessionUsecase struct {
+	repository  repository.SessionRepository
+	revocations auth.RevocationStore
+	tokenTTL    time.Duration
+}
+
+func NewSessionUsecase(repo repository.SessionRepository, revocations auth.RevocationStore,
+	tokenTTL time.Duration) SessionUsecase {
+	return SessionUsecase{
+		repository:  repo,
+		revocations: revocations,
+		tokenTTL:    tokenTTL,
+	}
+}
+
+func (su *SessionUsecase) GetSessions(userID int) ([]model.Session, error) {
+	return su.repository.GetActiveSessions(userID)
+}
+
+// RevokeSessions revoga as sessões indicadas (ou todas, sem ids) e invalida
+// imediatamente os access tokens já emitidos para elas
+func (su *SessionUsecase) RevokeSessions(userID int, ids ...string) ([]string, error) {
+	revoked, err := su.repository.RevokeSessions(userID, ids...)
+	if err != nil {
+		return nil, err
+	}
+
+	until := time.Now().Add(su.tokenTTL)
+	for _, id := range revoked {
+		su.revocations.Revoke(id, until)
+	}
+
+	return revoked, nil
+}
+
+// RevokeOtherSessions revoga todas as sessões do usuário, exceto a indicada em keep
+func (su *SessionUsecase) RevokeOtherSessions(userID int, keep string) ([]string, error) {
+	sessions, err := su.repository.GetActiveSessions(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, session := range sessions {
+		if session.ID != keep {
+			ids = append(ids, session.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return []string{}, nil
+	}
+
+	return su.RevokeSessions(userID, ids...)
+}
+
+// LoadRevocations repopula o RevocationStore com as revogações cujos tokens ainda não expiraram
+func (su *SessionUsecase) LoadRevocations() error {
+	ids, err := su.repository.GetRevokedSince(time.Now().Add(-su.tokenTTL))
+	if err != nil {
+		return err
+	}
+
+	until := time.Now().Add(su.tokenTTL)
+	for _, id := range ids {
+		su.revocations.Revoke(id, until)
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/repository"
+)
+
+// fila dos jobs que executam tasks, separada para não atrasar os emails
+const taskQueue = "tasks"
+
+// TaskResult é o que uma task bem-sucedida produz: o local do arquivo gerado, se
+// houver, e as falhas parciais que não impediram a conclusão
+type TaskResult struct {
+	URL    string
+	Errors []string
+}
+
+// TaskFunc executa uma task; report registra o percentual concluído
+type TaskFunc func(ctx context.Context, payload model.TaskPayload, report func(percent int)) (TaskResult, error)
+
+type TaskUsecase struct {
+	taskRepository repository.TaskRepository
+	jobRepository  repository.JobRepository
+	transactor     repository.Transactor
+}
+
+func NewTaskUsecase(taskRepo repository.TaskRepository, jobRepo repository.JobRepository,
+	transactor repository.Transactor) TaskUsecase {
+	return TaskUsecase{
+		taskRepository: taskRepo,
+		jobRepository:  jobRepo,
+		transactor:     transactor,
+	}
+}
+
+// Submit registra a task e enfileira o job que a executa, na mesma transação.
+// O job tem uma única tentativa: uma task que falha fica como failed até ser
+// submetida de novo (ou o job ser reprocessado pelo painel de jobs).
+func (tu *TaskUsecase) Submit(kind string, input any, actor model.Actor) (model.Task, error) {
+	var encoded json.RawMessage
+	if input != nil {
+		var err error
+		if encoded, err = json.Marshal(input); err != nil {
+			return model.Task{}, err
+		}
+	}
+
+	var task model.Task
+	err := tu.transactor.WithinTx(func(tx *sql.Tx) error {
+		tasks := tu.taskRepository.WithTx(tx)
+		var err error
+		task, err = tasks.Create(kind, actor.ID)
+		if err != nil {
+			return err
+		}
+
+		payload, err := json.Marshal(model.TaskPayload{TaskID: task.ID, Actor: actor, Input: encoded})
+		if err != nil {
+			return err
+		}
+
+		jobs := tu.jobRepository.WithTx(tx)
+		_, err = jobs.Enqueue(model.Job{
+			Queue:       taskQueue,
+			Kind:        kind,
+			Payload:     payload,
+			MaxAttempts: 1,
+		})
+		return err
+	})
+
+	return task, err
+}
+
+func (tu *TaskUsecase) GetTask(id int64) (*model.Task, error) {
+	return tu.taskRepository.GetTask(id)
+}
+
+// Handler adapta fn para o worker de jobs, mantendo status, progresso e
+// resultado da task atualizados
+func (tu *TaskUsecase) Handler(fn TaskFunc) func(ctx context.Context, raw json.RawMessage) error {
+	return func(ctx context.Context, raw json.RawMessage) (err error) {
+		var payload model.TaskPayload
+		if err := json.Unmarshal(raw, &payload); err != nil {
+			return err
+		}
+
+		if err := tu.taskRepository.Start(payload.TaskID); err != nil {
+			return err
+		}
+
+		// só grava quando o percentual avança; 100 fica reservado para a conclusão
+		reported := 0
+		report := func(percent int) {
+			percent = min(percent, 99)
+			if percent <= reported {
+				return
+			}
+			if err := tu.taskRepository.SetProgress(payload.TaskID, percent); err == nil {
+				reported = percent
+			}
+		}
+
+		// um panic em fn não pode deixar a task como running para sempre
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v", r)
+				if finishErr := tu.taskRepository.Finish(payload.TaskID, model.TaskFailed, "", []string{err.Error()}); finishErr != nil {
+					err = finishErr
+				}
+			}
+		}()
+
+		result, err := fn(ctx, payload, report)
+		if err != nil {
+			if finishErr := tu.taskRepository.Finish(payload.TaskID, model.TaskFailed, "", []string{err.Error()}); finishErr != nil {
+				return finishErr
+			}
+			return err
+		}
+
+		return tu.taskRepository.Finish(payload.TaskID, model.TaskSucceeded, result.URL, result.Errors)
+	}
+}
@@ -0,0 +1,75 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/quota"
+	"github.com/pytsx/goapi/repository"
+)
+
+type UsageUsecase struct {
+	counter        quota.Counter
+	userRepository repository.UserRepository
+	meteredKeys    []string
+	limits         *quota.Limits
+}
+
+func NewUsageUsecase(counter quota.Counter, userRepo repository.UserRepository,
+	meteredKeys []string, limits *quota.Limits) UsageUsecase {
+	return UsageUsecase{
+		counter:        counter,
+		userRepository: userRepo,
+		meteredKeys:    meteredKeys,
+		limits:         limits,
+	}
+}
+
+// Report devolve o consumo de todas as cotas: usuários e requisições do dia por chave
+func (uu *UsageUsecase) Report() (model.UsageReport, error) {
+	users, err := uu.userRepository.CountUsers()
+	if err != nil {
+		return model.UsageReport{}, err
+	}
+
+	keys, err := uu.keysUsage(uu.meteredKeys)
+	if err != nil {
+		return model.UsageReport{}, err
+	}
+
+	return model.UsageReport{
+		Users:   model.QuotaUsage{Used: users, Limit: int64(uu.limits.MaxUsers())},
+		APIKeys: keys,
+		ResetAt: quota.NextReset(),
+	}, nil
+}
+
+// KeyUsage devolve o consumo do dia da chave informada
+func (uu *UsageUsecase) KeyUsage(key string) (model.APIKeyUsage, error) {
+	usage, err := uu.keysUsage([]string{key})
+	if err != nil {
+		return model.APIKeyUsage{}, err
+	}
+	return usage[0], nil
+}
+
+func (uu *UsageUsecase) keysUsage(keys []string) ([]model.APIKeyUsage, error) {
+	ids := make([]string, len(keys))
+	for i, key := range keys {
+		ids[i] = quota.KeyID(key)
+	}
+
+	counts, err := uu.counter.Usage(context.Background(), ids, quota.Today())
+	if err != nil {
+		return nil, err
+	}
+
+	usage := make([]model.APIKeyUsage, len(ids))
+	for i, id := range ids {
+		usage[i] = model.APIKeyUsage{
+			KeyID:         id,
+			RequestsToday: model.QuotaUsage{Used: counts[id], Limit: int64(uu.limits.DailyRequests())},
+		}
+	}
+	return usage, nil
+}
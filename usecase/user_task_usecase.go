@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/repository"
+	"github.com/pytsx/goapi/storage"
+)
+
+// limite de linhas por importação; o lote inteiro fica no payload do job
+const maxImportRows = 10000
+
+type anonymizeInput struct {
+	UserID int `json:"user_id"`
+}
+
+// UserTaskUsecase reúne as operações em massa sobre usuários, executadas como tasks
+type UserTaskUsecase struct {
+	userUsecase    UserUsecase
+	userRepository repository.UserRepository
+	sessionUsecase SessionUsecase
+	taskUsecase    TaskUsecase
+	exportStore    storage.BlobStore
+}
+
+func NewUserTaskUsecase(userUsecase UserUsecase, userRepo repository.UserRepository, sessionUsecase SessionUsecase,
+	taskUsecase TaskUsecase, exportStore storage.BlobStore) UserTaskUsecase {
+	return UserTaskUsecase{
+		userUsecase:    userUsecase,
+		userRepository: userRepo,
+		sessionUsecase: sessionUsecase,
+		taskUsecase:    taskUsecase,
+		exportStore:    exportStore,
+	}
+}
+
+func (ut *UserTaskUsecase) RequestExport(actor model.Actor) (model.Task, error) {
+	return ut.taskUsecase.Submit(model.TaskUserExport, nil, actor)
+}
+
+// Export grava todos os usuários em um arquivo JSON no armazenamento de exportações
+func (ut *UserTaskUsecase) Export(ctx context.Context, payload model.TaskPayload, report func(int)) (TaskResult, error) {
+	users := ut.userRepository.WithContext(ctx)
+	total, err := users.CountUsers()
+	if err != nil {
+		return TaskResult{}, err
+	}
+
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(writeUsers(ctx, writer, users, total, report))
+	}()
+
+	url, err := ut.exportStore.Put(ctx, "users-"+strconv.FormatInt(payload.TaskID, 10)+".json", reader)
+	// libera a goroutine de escrita caso o Put tenha parado no meio
+	reader.Close()
+	if err != nil {
+		return TaskResult{}, err
+	}
+
+	return TaskResult{URL: url}, nil
+}
+
+func writeUsers(ctx context.Context, w io.Writer, users repository.UserRepository, total int64, report func(int)) error {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(w)
+	var written int64
+	err := users.ExportUsers(ctx, func(user model.User) error {
+		if written > 0 {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		if err := encoder.Encode(user); err != nil {
+			return err
+		}
+
+		written++
+		if total > 0 {
+			report(int(written * 100 / total))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(w, "]")
+	return err
+}
+
+// RequestImport agenda a criação dos usuários em lote. As senhas são descartadas
+// para não ficarem gravadas no payload do job.
+func (ut *UserTaskUsecase) RequestImport(users []model.User, actor model.Actor) (model.Task, error) {
+	if len(users) == 0 || len(users) > maxImportRows {
+		return model.Task{}, ValidationError{Fields: map[string]string{
+			"users": "informe entre 1 e " + strconv.Itoa(maxImportRows) + " usuários",
+		}}
+	}
+
+	for i := range users {
+		users[i].Password = ""
+	}
+
+	return ut.taskUsecase.Submit(model.TaskUserImport, users, actor)
+}
+
+// Import cria os usuários um a um, pelas mesmas regras do cadastro. Linhas
+// rejeitadas são listadas nos erros da task sem interromper as demais.
+func (ut *UserTaskUsecase) Import(ctx context.Context, payload model.TaskPayload, report func(int)) (TaskResult, error) {
+	var users []model.User
+	if err := json.Unmarshal(payload.Input, &users); err != nil {
+		return TaskResult{}, err
+	}
+
+	var result TaskResult
+	creator := ut.userUsecase.WithContext(ctx)
+	for i, user := range users {
+		if _, err := creator.CreateUser(user, payload.Actor); err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("linha %d (%s): %v", i+1, user.Email, err))
+		}
+		report((i + 1) * 100 / len(users))
+	}
+
+	return result, nil
+}
+
+func (ut *UserTaskUsecase) RequestAnonymize(id int, actor model.Actor) (model.Task, error) {
+	user, err := ut.userRepository.GetUser(id)
+	if err != nil {
+		return model.Task{}, err
+	}
+	if user == nil {
+		return model.Task{}, ErrUserNotFound
+	}
+
+	return ut.taskUsecase.Submit(model.TaskUserAnonymize, anonymizeInput{UserID: id}, actor)
+}
+
+// Anonymize apaga os dados pessoais do usuário e encerra as sessões dele
+func (ut *UserTaskUsecase) Anonymize(ctx context.Context, payload model.TaskPayload, report func(int)) (TaskResult, error) {
+	var input anonymizeInput
+	if err := json.Unmarshal(payload.Input, &input); err != nil {
+		return TaskResult{}, err
+	}
+
+	users := ut.userUsecase.WithContext(ctx)
+	if err := users.AnonymizeUser(input.UserID, payload.Actor); err != nil {
+		return TaskResult{}, err
+	}
+	report(50)
+
+	_, err := ut.sessionUsecase.RevokeSessions(input.UserID)
+	return TaskResult{}, err
+}
@@ -1,34 +1,527 @@
 package usecase
 
 import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pytsx/goapi/events"
 	"github.com/pytsx/goapi/model"
+	"github.com/pytsx/goapi/moderation"
+	"github.com/pytsx/goapi/normalize"
+	"github.com/pytsx/goapi/presence"
+	"github.com/pytsx/goapi/quota"
 	"github.com/pytsx/goapi/repository"
+	"github.com/pytsx/goapi/storage"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// similaridade mínima de trigramas para considerar dois nomes possíveis duplicados
+	duplicateNameThreshold = 0.45
+	maxDuplicateMatches    = 5
+	// entradas de auditoria devolvidas em GET /users/:id/related
+	relatedActivityLimit = 10
+	// alterações mais recentes que isso ficam para a próxima chamada: uma transação
+	// ainda aberta pode gravar um updated_at anterior ao cursor já entregue
+	changesSettleDelay = 5 * time.Second
+)
+
+var (
+	ErrContentRejected = errors.New("conteúdo rejeitado pela moderação")
+	ErrUserNotFound    = errors.New("usuário não encontrado")
+	ErrEmailTaken      = errors.New("já existe um usuário com este email")
+	// a cota de usuários configurada foi atingida
+	ErrUserQuotaExceeded = errors.New("cota de usuários atingida")
+)
+
+// DeleteStrategy define o que acontece com os registros de uma relação quando o usuário é removido
+type DeleteStrategy string
+
+const (
+	// impede a remoção enquanto houver registros
+	DeleteBlock DeleteStrategy = "block"
+	// apaga os registros junto com o usuário
+	DeleteCascade DeleteStrategy = "cascade"
+	// mantém os registros, desvinculados do usuário
+	DeleteOrphan DeleteStrategy = "orphan"
 )
 
+// estratégia de cada relação de users, aplicadas nesta ordem
+var userDeleteStrategies = []struct {
+	name     string
+	strategy DeleteStrategy
+}{
+	{"preferences", DeleteCascade},
+	{"login_history", DeleteCascade},
+	{"sessions", DeleteCascade},
+	// o aceite dos termos é prova legal e sobrevive ao titular
+	{"consents", DeleteOrphan},
+	// o uso do convite continua contando no relatório
+	{"invitations", DeleteOrphan},
+}
+
+// DependentsError lista as relações que impedem a remoção, com a quantidade de
+// registros em cada uma (0 quando desconhecida)
+type DependentsError struct {
+	Relations map[string]int64
+}
+
+func (e DependentsError) Error() string {
+	names := make([]string, 0, len(e.Relations))
+	for name := range e.Relations {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return "o usuário possui registros dependentes: " + strings.Join(names, ", ")
+}
+
 type UserUsecase struct {
-	repository repository.UserRepository
+	repository      repository.UserRepository
+	auditRepository repository.AuditRepository
+	jobRepository   repository.JobRepository
+	transactor      repository.Transactor
+	moderator       moderation.Moderator
+	normalizer      normalize.Pipeline
+	presence        presence.Tracker
+	dispatcher      *events.Dispatcher
+	limits          *quota.Limits
+	invitations     InvitationUsecase
+	// avatares publicados e em quarentena, apagados na anonimização
+	avatarStores []storage.BlobStore
+	ctx          context.Context
 }
 
-func NewUserUsecase(repo repository.UserRepository) UserUsecase {
+func NewUserUsecase(repo repository.UserRepository, auditRepo repository.AuditRepository,
+	jobRepo repository.JobRepository, transactor repository.Transactor, moderator moderation.Moderator,
+	normalizer normalize.Pipeline, tracker presence.Tracker, dispatcher *events.Dispatcher, limits *quota.Limits,
+	invitations InvitationUsecase, avatarStores []storage.BlobStore) UserUsecase {
 	return UserUsecase{
-		repository: repo,
+		repository:      repo,
+		auditRepository: auditRepo,
+		jobRepository:   jobRepo,
+		transactor:      transactor,
+		moderator:       moderator,
+		normalizer:      normalizer,
+		presence:        tracker,
+		dispatcher:      dispatcher,
+		limits:          limits,
+		invitations:     invitations,
+		avatarStores:    avatarStores,
+		ctx:             context.Background(),
+	}
+}
+
+// WithContext devolve uma cópia cujas consultas e chamadas externas respeitam o
+// deadline de ctx, normalmente o da requisição
+func (uu UserUsecase) WithContext(ctx context.Context) UserUsecase {
+	uu.repository = uu.repository.WithContext(ctx)
+	uu.auditRepository = uu.auditRepository.WithContext(ctx)
+	uu.jobRepository = uu.jobRepository.WithContext(ctx)
+	uu.transactor = uu.transactor.WithContext(ctx)
+	uu.invitations = uu.invitations.WithContext(ctx)
+	uu.ctx = ctx
+	return uu
+}
+
+func (uu *UserUsecase) GetUsers(filter model.UserFilter, limit, offset int) ([]model.User, error) {
+	if err := validateUserFilter(filter); err != nil {
+		return nil, err
+	}
+
+	users, err := uu.repository.GetUsers(filter, limit, offset)
+	if err != nil {
+		return users, err
+	}
+
+	uu.markOnline(users)
+	return users, nil
+}
+
+func (uu *UserUsecase) StreamUsers(maxRows int, fn func(model.User) error) (bool, error) {
+	// uma única consulta de presença para todo o stream, em vez de uma por linha
+	online := map[int]bool{}
+	ids, err := uu.presence.List(context.Background())
+	if err != nil {
+		log.Printf("presença indisponível, is_online será omitido: %v", err)
+	}
+	for _, id := range ids {
+		online[id] = true
+	}
+
+	return uu.repository.StreamUsers(maxRows, func(user model.User) error {
+		user.IsOnline = online[user.ID]
+		return fn(user)
+	})
+}
+
+// markOnline preenche is_online. Falhas do rastreador de presença não impedem a listagem
+func (uu *UserUsecase) markOnline(users []model.User) {
+	if len(users) == 0 {
+		return
+	}
+
+	ids := make([]int, len(users))
+	for i, user := range users {
+		ids[i] = user.ID
+	}
+
+	online, err := uu.presence.Online(uu.ctx, ids)
+	if err != nil {
+		log.Printf("presença indisponível, is_online será omitido: %v", err)
+		return
+	}
+	for i := range users {
+		users[i].IsOnline = online[users[i].ID]
 	}
 }
 
-func (uu *UserUsecase) GetUsers() ([]model.User, error) {
-	return uu.repository.GetUsers()
+// CreateUser normaliza, valida e modera os dados e, numa única transação, grava o usuário,
+// a entrada de auditoria e o job do email de boas-vindas. O evento user.created
+// só é publicado depois do commit.
+func (uu *UserUsecase) CreateUser(user model.User, actor model.Actor) (model.User, error) {
+	return uu.createUser(user, actor, false)
 }
 
-func (uu *UserUsecase) CreateUser(user model.User) (model.User, error) {
-	uid, err := uu.repository.CreateUser(user)
+// Register é o cadastro público. O convite informado é consumido na mesma transação;
+// com o cadastro restrito a convidados, sem um convite válido retorna ErrInvitationRequired.
+// Com o cadastro aberto, um convite inválido é ignorado.
+func (uu *UserUsecase) Register(user model.User, actor model.Actor) (model.User, error) {
+	return uu.createUser(user, actor, true)
+}
+
+func (uu *UserUsecase) createUser(user model.User, actor model.Actor, public bool) (model.User, error) {
+	// admins cadastram sem convite; o código só vale no cadastro público
+	inviteCode := ""
+	if public {
+		inviteCode = user.InviteCode
+	}
+	user.InviteCode = ""
+	if public && inviteCode == "" && uu.invitations.Required() {
+		return model.User{}, ErrInvitationRequired
+	}
+
+	uu.normalizer.Apply(&user)
+
+	if err := validateUser(user); err != nil {
+		return model.User{}, err
+	}
+
+	result, err := uu.moderator.Moderate(uu.ctx, user.Name)
 	if err != nil {
 		return model.User{}, err
 	}
 
-	user.ID = uid
-	return user, nil
+	switch result.Verdict {
+	case moderation.Reject:
+		return model.User{}, ErrContentRejected
+	case moderation.Flag:
+		// o cadastro segue, mas o perfil fica aguardando revisão
+		user.ModerationStatus = model.ModerationFlagged
+		user.ModerationReason = result.Reason
+	default:
+		user.ModerationStatus = model.ModerationApproved
+	}
+
+	if user.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return model.User{}, err
+		}
+		user.PasswordHash = string(hash)
+		user.Password = ""
+	}
+
+	// valor provisório para a resposta do cadastro; o assinante de UserCreated
+	// recalcula com o que ficou no banco (ex.: email já suprimido por bounce)
+	user.ProfileCompleteness = profileCompleteness(model.ProfileFacts{
+		HasAvatar:        user.ImgURL != "",
+		EmailDeliverable: true,
+		HasPassword:      user.PasswordHash != "",
+	})
+
+	var created model.User
+	err = uu.transactor.WithinTx(func(tx *sql.Tx) error {
+		users := uu.repository.WithTx(tx)
+		if maxUsers := uu.limits.MaxUsers(); maxUsers > 0 {
+			// cota branda: cadastros concorrentes podem ultrapassá-la por poucos registros
+			count, err := users.CountUsers()
+			if err != nil {
+				return err
+			}
+			if count >= int64(maxUsers) {
+				return ErrUserQuotaExceeded
+			}
+		}
+
+		var invitationID int64
+		if inviteCode != "" {
+			var err error
+			invitationID, err = uu.invitations.redeem(tx, inviteCode)
+			if err != nil {
+				return err
+			}
+			if invitationID == 0 && uu.invitations.Required() {
+				return ErrInvitationRequired
+			}
+		}
+
+		var err error
+		created, err = users.CreateUser(user)
+		if errors.Is(err, repository.ErrDuplicateEmail) {
+			return ErrEmailTaken
+		}
+		if err != nil {
+			return err
+		}
+
+		metadata := map[string]any{
+			"email":             created.Email,
+			"moderation_status": user.ModerationStatus,
+		}
+		if invitationID != 0 {
+			if err := uu.invitations.recordUse(tx, invitationID, created.ID); err != nil {
+				return err
+			}
+			metadata["invitation_id"] = invitationID
+		}
+
+		audit := uu.auditRepository.WithTx(tx)
+		err = audit.Record(model.AuditEntry{
+			Actor:    actor,
+			Action:   "user.created",
+			Entity:   "user",
+			EntityID: strconv.Itoa(created.ID),
+			Metadata: metadata,
+		})
+		if err != nil {
+			return err
+		}
+
+		payload, err := json.Marshal(model.WelcomeEmailPayload{UserID: created.ID, Locale: user.Locale})
+		if err != nil {
+			return err
+		}
+
+		queue := uu.jobRepository.WithTx(tx)
+		_, err = queue.Enqueue(model.Job{Kind: model.JobWelcomeEmail, Payload: payload})
+		return err
+	})
+	if err != nil {
+		return model.User{}, err
+	}
+
+	uu.dispatcher.Publish(events.UserCreated, created)
+	return created, nil
 }
 
 func (uu *UserUsecase) GetUser(id int) (*model.User, error) {
-	return uu.repository.GetUser(id)
+	user, err := uu.repository.GetUser(id)
+	if err != nil || user == nil {
+		return user, err
+	}
+
+	users := []model.User{*user}
+	uu.markOnline(users)
+	return &users[0], nil
+}
+
+// GetChanges devolve uma página de alterações posteriores ao cursor e o cursor da
+// próxima chamada. Sem alterações, o cursor devolvido é o mesmo recebido.
+func (uu *UserUsecase) GetChanges(after model.ChangeCursor, limit int) ([]model.UserChange, model.ChangeCursor, bool, error) {
+	changes, err := uu.repository.GetChanges(after, changesSettleDelay, limit+1)
+	if err != nil {
+		return nil, after, false, err
+	}
+
+	hasMore := len(changes) > limit
+	if hasMore {
+		changes = changes[:limit]
+	}
+	if len(changes) > 0 {
+		last := changes[len(changes)-1]
+		after = model.ChangeCursor{At: last.At, UserID: last.UserID}
+	}
+
+	return changes, after, hasMore, nil
+}
+
+// GetRelated monta a visão do usuário para painéis: contagens numa única consulta,
+// a atividade recente e a presença. Retorna nil quando o usuário não existe.
+func (uu *UserUsecase) GetRelated(id int) (*model.UserRelated, error) {
+	related, err := uu.repository.GetRelated(id)
+	if err != nil || related == nil {
+		return related, err
+	}
+
+	related.RecentActivity, err = uu.auditRepository.ListForUser(id, relatedActivityLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	online, err := uu.presence.Online(uu.ctx, []int{id})
+	if err != nil {
+		log.Printf("presença indisponível, is_online será omitido: %v", err)
+	}
+	related.IsOnline = online[id]
+
+	return related, nil
+}
+
+// DeleteUser apaga o usuário tratando cada relação pela estratégia definida em
+// userDeleteStrategies e registra a remoção na trilha de auditoria, na mesma transação
+func (uu *UserUsecase) DeleteUser(id int, actor model.Actor) error {
+	return uu.transactor.WithinTx(func(tx *sql.Tx) error {
+		users := uu.repository.WithTx(tx)
+
+		user, err := users.GetUser(id)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return ErrUserNotFound
+		}
+
+		blocked := map[string]int64{}
+		affected := map[string]any{}
+		for _, relation := range userDeleteStrategies {
+			var count int64
+			switch relation.strategy {
+			case DeleteBlock:
+				count, err = users.CountRelated(relation.name, id)
+				if count > 0 {
+					blocked[relation.name] = count
+				}
+			case DeleteCascade:
+				count, err = users.DeleteRelated(relation.name, id)
+			case DeleteOrphan:
+				count, err = users.OrphanRelated(relation.name, id)
+			}
+			if err != nil {
+				return err
+			}
+			if count > 0 && relation.strategy != DeleteBlock {
+				affected[relation.name] = map[string]any{"strategy": relation.strategy, "rows": count}
+			}
+		}
+		if len(blocked) > 0 {
+			return DependentsError{Relations: blocked}
+		}
+
+		_, err = users.DeleteUser(id)
+		var referenced repository.ReferencedError
+		if errors.As(err, &referenced) {
+			// relação sem estratégia definida: bloqueia em vez de expor o erro do banco
+			return DependentsError{Relations: map[string]int64{referenced.Table: 0}}
+		}
+		if err != nil {
+			return err
+		}
+
+		audit := uu.auditRepository.WithTx(tx)
+		return audit.Record(model.AuditEntry{
+			Actor:    actor,
+			Action:   "user.deleted",
+			Entity:   "user",
+			EntityID: strconv.Itoa(id),
+			Metadata: affected,
+		})
+	})
+}
+
+// AnonymizeUser apaga os dados pessoais do usuário mantendo o registro, com a
+// entrada de auditoria na mesma transação. Os arquivos de avatar são apagados por último,
+// ainda dentro dela: se falharem, nada é gravado e a task pode ser repetida
+func (uu *UserUsecase) AnonymizeUser(id int, actor model.Actor) error {
+	err := uu.transactor.WithinTx(func(tx *sql.Tx) error {
+		users := uu.repository.WithTx(tx)
+		anonymized, err := users.AnonymizeUser(id)
+		if err != nil {
+			return err
+		}
+		if !anonymized {
+			return ErrUserNotFound
+		}
+
+		audit := uu.auditRepository.WithTx(tx)
+		err = audit.Record(model.AuditEntry{
+			Actor:    actor,
+			Action:   "user.anonymized",
+			Entity:   "user",
+			EntityID: strconv.Itoa(id),
+		})
+		if err != nil {
+			return err
+		}
+
+		// as chaves dos avatares começam com o id do usuário (ver AvatarUsecase)
+		for _, store := range uu.avatarStores {
+			if err := store.DeletePrefix(uu.ctx, strconv.Itoa(id)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// o avatar e a senha foram apagados
+	uu.dispatcher.Publish(events.ProfileUpdated, model.ProfileUpdated{UserID: id})
+	return nil
+}
+
+// CheckDuplicates procura usuários existentes com o mesmo email normalizado ou
+// nome parecido, para o cliente avisar antes de criar um quase duplicado
+func (uu *UserUsecase) CheckDuplicates(check model.DuplicateCheck) ([]model.DuplicateMatch, error) {
+	candidate := model.User{Name: check.Name, Email: check.Email}
+	uu.normalizer.Apply(&candidate)
+
+	if candidate.Name == "" && candidate.Email == "" {
+		return nil, ValidationError{Fields: map[string]string{
+			"name":  "informe o nome, o email ou ambos",
+			"email": "informe o nome, o email ou ambos",
+		}}
+	}
+
+	matches := []model.DuplicateMatch{}
+	seen := map[int]bool{}
+
+	if candidate.Email != "" {
+		byEmail, err := uu.repository.FindByCanonicalEmail(candidate.Email, maxDuplicateMatches)
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range byEmail {
+			seen[match.User.ID] = true
+			matches = append(matches, match)
+		}
+	}
+
+	if candidate.Name != "" {
+		var byName []model.DuplicateMatch
+		err := uu.transactor.WithinTx(func(tx *sql.Tx) error {
+			users := uu.repository.WithTx(tx)
+			var err error
+			byName, err = users.FindSimilarNames(candidate.Name, duplicateNameThreshold, maxDuplicateMatches)
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, match := range byName {
+			if !seen[match.User.ID] {
+				matches = append(matches, match)
+			}
+		}
+	}
+
+	return matches, nil
 }
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"net/mail"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/pytsx/goapi/model"
+)
+
+// ValidationError lista os campos inválidos e o motivo de cada um
+type ValidationError struct {
+	Fields map[string]string
+}
+
+func (e ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for field := range e.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	return "dados inválidos: " + strings.Join(fields, ", ")
+}
+
+func validateUser(user model.User) error {
+	fields := map[string]string{}
+
+	name := strings.TrimSpace(user.Name)
+	if name == "" {
+		fields["name"] = "obrigatório"
+	} else if len(name) > 255 {
+		fields["name"] = "deve ter no máximo 255 caracteres"
+	}
+
+	if address, err := mail.ParseAddress(user.Email); err != nil || address.Address != user.Email {
+		fields["email"] = "deve ser um endereço de email válido"
+	}
+
+	if user.Password != "" {
+		if reason := checkPasswordPolicy(user.Password); reason != "" {
+			fields["password"] = reason
+		}
+	}
+
+	if user.ImgURL != "" {
+		parsed, err := url.Parse(string(user.ImgURL))
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			fields["img_url"] = "deve ser uma URL http(s) válida"
+		}
+	}
+
+	if len(fields) > 0 {
+		return ValidationError{Fields: fields}
+	}
+	return nil
+}
+
+func validateUserFilter(filter model.UserFilter) error {
+	fields := map[string]string{}
+
+	if field := strings.TrimPrefix(filter.Sort, "-"); filter.Sort != "" && !slices.Contains(model.UserSortFields, field) {
+		fields["sort"] = "deve ser um de " + strings.Join(model.UserSortFields, ", ") + ", opcionalmente com prefixo -"
+	}
+
+	if filter.CreatedAfter != nil && filter.CreatedBefore != nil && !filter.CreatedAfter.Before(*filter.CreatedBefore) {
+		fields["created_before"] = "deve ser posterior a created_after"
+	}
+
+	for param, value := range map[string]*int{"completeness_min": filter.MinCompleteness, "completeness_max": filter.MaxCompleteness} {
+		if value != nil && (*value < 0 || *value > 100) {
+			fields[param] = "deve estar entre 0 e 100"
+		}
+	}
+	if filter.MinCompleteness != nil && filter.MaxCompleteness != nil && *filter.MinCompleteness > *filter.MaxCompleteness {
+		fields["completeness_max"] = "deve ser maior ou igual a completeness_min"
+	}
+
+	if len(fields) > 0 {
+		return ValidationError{Fields: fields}
+	}
+	return nil
+}
+
+func validateInvitation(request model.InvitationRequest) error {
+	fields := map[string]string{}
+
+	if request.MaxUses < 1 || request.MaxUses > maxInvitationUses {
+		fields["max_uses"] = "deve estar entre 1 e " + strconv.Itoa(maxInvitationUses)
+	}
+	if !request.ExpiresAt.IsZero() && !request.ExpiresAt.After(time.Now()) {
+		fields["expires_at"] = "deve estar no futuro"
+	}
+	if utf8.RuneCountInString(request.Note) > 255 {
+		fields["note"] = "deve ter no máximo 255 caracteres"
+	}
+
+	if len(fields) > 0 {
+		return ValidationError{Fields: fields}
+	}
+	return nil
+}
+
+// checkPasswordPolicy devolve o motivo da recusa, ou vazio se a senha atende à política
+func checkPasswordPolicy(password string) string {
+	if utf8.RuneCountInString(password) < 8 {
+		return "deve ter pelo menos 8 caracteres"
+	}
+	// o bcrypt ignora o que passa de 72 bytes
+	if len(password) > 72 {
+		return "deve ter no máximo 72 bytes"
+	}
+	if !strings.ContainsFunc(password, unicode.IsLetter) || !strings.ContainsFunc(password, unicode.IsDigit) {
+		return "deve combinar letras e números"
+	}
+	return ""
+}